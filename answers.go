@@ -0,0 +1,176 @@
+package gojen
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Question is the kind of a yes/no question asked during load, build or
+// apply.
+type Question string
+
+const (
+	// QuestionOverrideDecl asks to override a declaration with the same name.
+	QuestionOverrideDecl Question = "override_decl"
+	// QuestionOverrideSeq asks to override a sequence with the same name.
+	QuestionOverrideSeq Question = "override_seq"
+	// QuestionDuplicatedContent asks to continue when the parsed content is
+	// already in the target file.
+	QuestionDuplicatedContent Question = "duplicated_content"
+	// QuestionCreateFile asks to create the target file when it does not
+	// exist.
+	QuestionCreateFile Question = "create_file"
+)
+
+// Answers contains the answers of the questions which are asked during load,
+// build or apply. In non-interactive mode every question must be answered by
+// it.
+type Answers struct {
+	// Args answers the missing required arguments.
+	Args Args `json:"args" yaml:"args"`
+	// Cases answers the case selections. Key is '<d_name>.<e_name>' of the
+	// node which has cases, value is the element name of the selected case.
+	// Several cases of a multi-select are separated by comma.
+	Cases map[string]string `json:"cases" yaml:"cases"`
+	// Confirm answers the yes/no questions. Key is either '<question>' or
+	// '<question>:<subject>', where subject is the file path or the name of
+	// the declaration/sequence. The more specific key wins.
+	Confirm map[string]bool `json:"confirm" yaml:"confirm"`
+	// ConfirmAll answers all yes/no questions which are not in Confirm.
+	ConfirmAll *bool `json:"confirm_all" yaml:"confirm_all"`
+	// Nodes answers the questions of the nodes of a sequence. Key is the id
+	// of the node, which is recorded by a build. They win over Args and Cases.
+	Nodes map[string]*NodeAnswers `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+}
+
+// NodeAnswers contains the answers of one node of a sequence.
+type NodeAnswers struct {
+	// Args answers the missing required arguments of the node.
+	Args Args `json:"args,omitempty" yaml:"args,omitempty"`
+	// Case answers the case selection of the node.
+	Case string `json:"case,omitempty" yaml:"case,omitempty"`
+}
+
+// NewAnswers returns empty answers.
+func NewAnswers() *Answers {
+	return &Answers{
+		Args:    make(Args),
+		Cases:   make(map[string]string),
+		Confirm: make(map[string]bool),
+		Nodes:   make(map[string]*NodeAnswers),
+	}
+}
+
+// LoadAnswers loads the answers from a YAML/JSON file.
+func LoadAnswers(path string) (*Answers, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	a := NewAnswers()
+	switch filepath.Ext(path) {
+	case ".json":
+		err = json.Unmarshal(b, a)
+	default:
+		err = yaml.Unmarshal(b, a)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing answers file '%s': %w", path, err)
+	}
+
+	if a.Args == nil {
+		a.Args = make(Args)
+	}
+	if a.Cases == nil {
+		a.Cases = make(map[string]string)
+	}
+	if a.Confirm == nil {
+		a.Confirm = make(map[string]bool)
+	}
+	if a.Nodes == nil {
+		a.Nodes = make(map[string]*NodeAnswers)
+	}
+
+	return a, nil
+}
+
+// Arg returns the answer of the missing argument of the node with the given
+// id.
+func (a *Answers) Arg(node, name string) (any, bool) {
+	if a == nil {
+		return nil, false
+	}
+
+	if n, ok := a.Nodes[node]; ok {
+		if v, ok := n.Args[name]; ok {
+			return v, true
+		}
+	}
+
+	v, ok := a.Args[name]
+	return v, ok
+}
+
+// Case returns the selected case of the node with the given id.
+func (a *Answers) Case(node, dName, eName string) (string, bool) {
+	if a == nil {
+		return "", false
+	}
+
+	if n, ok := a.Nodes[node]; ok && n.Case != "" {
+		return n.Case, true
+	}
+
+	c, ok := a.Cases[dName+"."+eName]
+	return c, ok
+}
+
+// args returns all answered args of the node with the given id.
+func (a *Answers) args(node string) Args {
+	if a == nil {
+		return Args{}
+	}
+
+	res := NewArgs(a.Args)
+	if n, ok := a.Nodes[node]; ok {
+		res.Merge(n.Args)
+	}
+
+	return res
+}
+
+// node returns the answers of the node with the given id.
+func (a *Answers) node(id string) *NodeAnswers {
+	if a.Nodes == nil {
+		a.Nodes = make(map[string]*NodeAnswers)
+	}
+	if _, ok := a.Nodes[id]; !ok {
+		a.Nodes[id] = &NodeAnswers{}
+	}
+
+	return a.Nodes[id]
+}
+
+// Confirmed returns the answer of the yes/no question about the subject.
+func (a *Answers) Confirmed(q Question, subject string) (bool, bool) {
+	if a == nil {
+		return false, false
+	}
+
+	if v, ok := a.Confirm[string(q)+":"+subject]; ok {
+		return v, true
+	}
+	if v, ok := a.Confirm[string(q)]; ok {
+		return v, true
+	}
+	if a.ConfirmAll != nil {
+		return *a.ConfirmAll, true
+	}
+
+	return false, false
+}
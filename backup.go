@@ -0,0 +1,192 @@
+package gojen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/cirius-go/gojen/lib/filemanager"
+)
+
+const (
+	backupDirName      = "backup"
+	backupManifestName = "manifest.yaml"
+)
+
+type (
+	// backupFile contains the original state of a file touched by Apply.
+	backupFile struct {
+		Path    string `yaml:"path"`
+		Created bool   `yaml:"created"`
+		Backup  string `yaml:"backup,omitempty"` // file name of the original content in the backup dir.
+	}
+
+	// backupManifest contains the original state of all files touched by the
+	// applies of a build.
+	backupManifest struct {
+		Files []*backupFile `yaml:"files"`
+	}
+)
+
+// backupDir returns the backup directory of the given build.
+func (g *Gojen) backupDir(buildID string) string {
+	return filepath.Join(g.cfg.storePath, buildID, backupDirName)
+}
+
+// loadBackup loads the backup manifest of the given build.
+func (g *Gojen) loadBackup(buildID string) (*backupManifest, error) {
+	b, err := os.ReadFile(filepath.Join(g.backupDir(buildID), backupManifestName))
+	if err != nil {
+		return nil, err
+	}
+
+	bk := &backupManifest{}
+	if err := yaml.Unmarshal(b, bk); err != nil {
+		return nil, err
+	}
+
+	return bk, nil
+}
+
+// backup saves the original content of the changed files beside the states
+// of the current build, and returns the manifest to be saved by saveBackup
+// once the changes are committed. The files already backed up by a previous
+// apply of the same build are kept as they are.
+func (g *Gojen) backup(changes []*filemanager.Change) (*backupManifest, error) {
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	dir := g.backupDir(g.buildID)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	bk, err := g.loadBackup(g.buildID)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+		bk = &backupManifest{}
+	}
+
+	existing := make(map[string]bool, len(bk.Files))
+	for _, f := range bk.Files {
+		existing[f.Path] = true
+	}
+
+	for _, c := range changes {
+		if existing[c.Path] {
+			continue
+		}
+
+		bf := &backupFile{
+			Path:    c.Path,
+			Created: c.Created,
+		}
+
+		if !c.Created {
+			bf.Backup = fmt.Sprintf("%d.orig", len(bk.Files))
+			if err := g.f.TruncWithContent(filepath.Join(dir, bf.Backup), c.Before); err != nil {
+				return nil, err
+			}
+		}
+
+		bk.Files = append(bk.Files, bf)
+	}
+
+	return bk, nil
+}
+
+// saveBackup writes the backup manifest of the current build, which marks the
+// build as applied for Undo.
+func (g *Gojen) saveBackup(bk *backupManifest) error {
+	if bk == nil {
+		return nil
+	}
+
+	b, err := yaml.Marshal(bk)
+	if err != nil {
+		return err
+	}
+
+	return g.f.TruncWithContent(filepath.Join(g.backupDir(g.buildID), backupManifestName), string(b))
+}
+
+// LastAppliedBuildID returns the id of the latest build which has a backup
+// to undo.
+func (g *Gojen) LastAppliedBuildID() (string, error) {
+	entries, err := os.ReadDir(g.cfg.storePath)
+	if err != nil {
+		return "", err
+	}
+
+	ids := []string{}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if g.f.FileExists(filepath.Join(g.backupDir(e.Name()), backupManifestName)) {
+			ids = append(ids, e.Name())
+		}
+	}
+
+	if len(ids) == 0 {
+		return "", fmt.Errorf("no applied build found in '%s'", g.cfg.storePath)
+	}
+
+	sort.Strings(ids)
+	return ids[len(ids)-1], nil
+}
+
+// Undo restores the original content of the files touched by the applies of
+// the given build and deletes the files which were created by it. If buildID
+// is empty, the latest applied build is undone.
+func (g *Gojen) Undo(buildID string) error {
+	if buildID == "" {
+		id, err := g.LastAppliedBuildID()
+		if err != nil {
+			return err
+		}
+		buildID = id
+	}
+
+	bk, err := g.loadBackup(buildID)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("build '%s' has nothing to undo", buildID)
+		}
+		return err
+	}
+
+	dir := g.backupDir(buildID)
+	for _, f := range bk.Files {
+		if f.Created {
+			if err := os.Remove(f.Path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			g.c.Infof(!g.cfg.silent, "Deleted file '%s'\n", f.Path)
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, f.Backup))
+		if err != nil {
+			return err
+		}
+		if err := g.f.TruncWithContent(f.Path, string(content)); err != nil {
+			return err
+		}
+		g.c.Infof(!g.cfg.silent, "Restored file '%s'\n", f.Path)
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+
+	g.c.Successf(!g.cfg.silent, "Undone build '%s'\n", buildID)
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newApplyCmd(o *options) *cobra.Command {
+	var from string
+
+	cmd := &cobra.Command{
+		Use:   "apply [decl.element...]",
+		Short: "Build the states of a sequence and apply them",
+		Long: `Build the states of a sequence and apply them.
+
+With --from, the states written into the state directory of a previous build
+are applied instead, without loading any declarations.`,
+		Example: `  gojen apply -d decls -a Domain=customer model.initModelFile dto.createDto
+  gojen apply -d decls -a Domain=customer --seq crud
+  gojen apply --from .gojen/20240102150405/state`,
+		RunE: func(cmd *cobra.Command, refs []string) error {
+			if from != "" {
+				if len(refs) > 0 || o.seqName != "" || o.replay != "" || o.resume != "" {
+					return fmt.Errorf("decl.element arguments, --seq, --replay and --resume can not be used with --from")
+				}
+
+				g, err := o.newBareGojen()
+				if err != nil {
+					return err
+				}
+
+				return g.ApplyFrom(from)
+			}
+
+			g, err := o.newGojen()
+			if err != nil {
+				return err
+			}
+
+			if err := o.build(g, refs); err != nil {
+				return err
+			}
+
+			return g.Apply()
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "state directory of a previous build to apply, e.g. .gojen/<build>/state")
+
+	return cmd
+}
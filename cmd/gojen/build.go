@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newBuildCmd(o *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "build [decl.element...]",
+		Short: "Build the states of a sequence without applying them",
+		Long: `Build the states of a sequence without applying them.
+
+The sequence is created from the positional arguments in form of decl.element,
+or loaded by name with --seq. Each built state is written into the store
+directory.`,
+		Example: `  gojen build -d decls -a Domain=customer model.initModelFile dto.createDto
+  gojen build -d decls -a Domain=customer --seq crud`,
+		RunE: func(cmd *cobra.Command, refs []string) error {
+			g, err := o.newGojen()
+			if err != nil {
+				return err
+			}
+
+			return o.build(g, refs)
+		},
+	}
+}
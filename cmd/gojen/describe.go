@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cirius-go/gojen"
+	"github.com/cirius-go/gojen/util"
+)
+
+func newDescribeCmd(o *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "describe decl|seq",
+		Short: "Describe a declaration and its elements, or a sequence",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, names []string) error {
+			g, err := o.newGojen()
+			if err != nil {
+				return err
+			}
+
+			w := cmd.OutOrStdout()
+
+			d := g.Decl(names[0])
+			if d == nil {
+				return describeSeq(w, g, names[0])
+			}
+
+			fmt.Fprintf(w, "Name:        %s\n", d.Name)
+			fmt.Fprintf(w, "Description: %s\n", d.Description)
+			fmt.Fprintf(w, "Path:        %s\n", d.Path)
+			fmt.Fprintf(w, "Require:     %s\n", strings.Join(d.Require, ", "))
+			describeParams(w, "", "Params:", d.Params)
+			fmt.Fprintf(w, "Elements:\n")
+			for _, e := range d.Templates {
+				fmt.Fprintf(w, "  - %s (%s)\n", e.Name, e.Strategy)
+				if e.Path != "" {
+					fmt.Fprintf(w, "    path:    %s\n", e.Path)
+				}
+				if len(e.Require) > 0 {
+					fmt.Fprintf(w, "    require: %s\n", strings.Join(e.Require, ", "))
+				}
+				describeParams(w, "    ", "params:", e.Params)
+				for name, out := range e.Output {
+					fmt.Fprintf(w, "    output:  %s -> %s\n", name, out.Path)
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+// describeSeq prints the sequence with the given name.
+func describeSeq(w io.Writer, g *gojen.Gojen, name string) error {
+	var sd *gojen.S
+	for _, s := range g.Seqs() {
+		if s.Name == name {
+			sd = s
+		}
+	}
+	if sd == nil {
+		return fmt.Errorf("Declaration or sequence '%s' not found", name)
+	}
+
+	seq, err := g.Seq(sd.Name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Name:        %s\n", sd.Name)
+	fmt.Fprintf(w, "Description: %s\n", sd.Description)
+	fmt.Fprintf(w, "Sequence:\n%s\n", seq)
+
+	return nil
+}
+
+// describeParams prints the argument schemas.
+func describeParams(w io.Writer, indent, label string, params gojen.Params) {
+	if len(params) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(w, "%s%s\n", indent, label)
+	for _, name := range names {
+		p := params[name]
+		fmt.Fprintf(w, "%s  - %s (%s)", indent, name, util.IfValue(gojen.ParamTypeString, p.Type))
+		if p.Default != nil {
+			fmt.Fprintf(w, " default=%v", p.Default)
+		}
+		if len(p.Values) > 0 {
+			fmt.Fprintf(w, " values=[%s]", strings.Join(p.Values, ", "))
+		}
+		if p.Pattern != "" {
+			fmt.Fprintf(w, " pattern=%s", p.Pattern)
+		}
+		if p.Description != "" {
+			fmt.Fprintf(w, ": %s", p.Description)
+		}
+		fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newGraphCmd(o *options) *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "graph [decl.element...]",
+		Short: "Print a sequence as a Mermaid flowchart or a Graphviz DOT digraph",
+		Long: `Print a sequence as a Mermaid flowchart or a Graphviz DOT digraph.
+
+The edges are labeled with the selected case, the forwarded args and the temp
+args. Nothing is built.`,
+		Example: `  gojen graph -d decls --seq crud
+  gojen graph -d decls --seq crud --format dot | dot -Tsvg > crud.svg`,
+		RunE: func(cmd *cobra.Command, refs []string) error {
+			g, err := o.newGojen()
+			if err != nil {
+				return err
+			}
+
+			seq, err := o.newSeq(g, refs)
+			if err != nil {
+				return err
+			}
+
+			switch format {
+			case "mermaid":
+				fmt.Fprint(cmd.OutOrStdout(), seq.Mermaid())
+			case "dot":
+				fmt.Fprint(cmd.OutOrStdout(), seq.DOT())
+			default:
+				return fmt.Errorf("unknown format '%s', expected mermaid or dot", format)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "mermaid", "output format: mermaid or dot")
+
+	return cmd
+}
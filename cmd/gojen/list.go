@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newListCmd(o *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the loaded declarations and sequences",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			g, err := o.newGojen()
+			if err != nil {
+				return err
+			}
+
+			w := cmd.OutOrStdout()
+			fmt.Fprintf(w, "Declarations:\n")
+			for _, d := range g.Decls() {
+				eNames := make([]string, 0, len(d.Templates))
+				for _, e := range d.Templates {
+					eNames = append(eNames, e.Name)
+				}
+
+				fmt.Fprintf(w, "  %s\t%s\n", d.Name, d.Description)
+				fmt.Fprintf(w, "    elements: %s\n", strings.Join(eNames, ", "))
+			}
+
+			seqs := g.Seqs()
+			if len(seqs) == 0 {
+				return nil
+			}
+
+			fmt.Fprintf(w, "Sequences:\n")
+			for _, s := range seqs {
+				fmt.Fprintf(w, "  %s\t%s\n", s.Name, s.Description)
+			}
+
+			return nil
+		},
+	}
+}
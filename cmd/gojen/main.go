@@ -0,0 +1,30 @@
+// Command gojen loads template declarations from YAML/JSON directories and
+// builds or applies sequences of their elements.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/cirius-go/gojen"
+)
+
+func main() {
+	if err := newRootCmd().Execute(); err != nil {
+		printSnippet(err)
+		os.Exit(1)
+	}
+}
+
+// printSnippet prints the lines of the template which caused the error.
+func printSnippet(err error) {
+	var tErr *gojen.ErrTemplate
+	if !errors.As(err, &tErr) {
+		return
+	}
+
+	if snippet := tErr.Snippet(2); snippet != "" {
+		fmt.Fprintf(os.Stderr, "\n%s of '%s.%s':\n%s", tErr.Name, tErr.DName, tErr.EName, snippet)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cirius-go/gojen"
+)
+
+func newPlanCmd(o *options) *cobra.Command {
+	var from string
+
+	cmd := &cobra.Command{
+		Use:   "plan [decl.element...]",
+		Short: "Build the states of a sequence and print the diffs they would apply",
+		Long: `Build the states of a sequence and print the diffs they would apply.
+
+The states are applied to an in-memory copy of the target files, nothing is
+written to disk. With --from, the states written into the state directory of a
+previous build are planned instead.`,
+		Example: `  gojen plan -d decls -a Domain=customer model.initModelFile
+  gojen plan -d decls -a Domain=customer --seq crud
+  gojen plan --from .gojen/20240102150405/state`,
+		RunE: func(cmd *cobra.Command, refs []string) error {
+			var (
+				g   *gojen.Gojen
+				err error
+			)
+			if from != "" {
+				if len(refs) > 0 || o.seqName != "" || o.replay != "" || o.resume != "" {
+					return fmt.Errorf("decl.element arguments, --seq, --replay and --resume can not be used with --from")
+				}
+
+				if g, err = o.newBareGojen(); err != nil {
+					return err
+				}
+
+				states, err := gojen.LoadStates(from)
+				if err != nil {
+					return err
+				}
+				g.SetStates(states...)
+			} else {
+				if g, err = o.newGojen(); err != nil {
+					return err
+				}
+
+				if err := o.build(g, refs); err != nil {
+					return err
+				}
+			}
+
+			p, err := g.Plan()
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprint(cmd.OutOrStdout(), p)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "state directory of a previous build to plan, e.g. .gojen/<build>/state")
+
+	return cmd
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/cirius-go/gojen"
+	"github.com/cirius-go/gojen/lib/cli"
+)
+
+// options contains the flags shared by all sub commands.
+type options struct {
+	declDirs     []string
+	args         []string
+	argsFile     string
+	seqName      string
+	answersFile  string
+	cases        []string
+	yes          bool
+	ci           bool
+	keepGoing    bool
+	missingKey   string
+	replay       string
+	resume       string
+	recording    *gojen.Recording
+	storePath    string
+	commentQuote string
+	silent       bool
+	noColor      bool
+}
+
+func newRootCmd() *cobra.Command {
+	o := &options{}
+
+	cmd := &cobra.Command{
+		Use:          "gojen",
+		Short:        "Generate project templates from gojen declarations",
+		SilenceUsage: true,
+	}
+
+	f := cmd.PersistentFlags()
+	f.StringSliceVarP(&o.declDirs, "decl", "d", []string{".gojen/decls"}, "directories to load the declarations from")
+	f.StringArrayVarP(&o.args, "arg", "a", nil, "argument in form of key=value, can be repeated")
+	f.StringVar(&o.argsFile, "args-file", "", "YAML/JSON file which contains the arguments")
+	f.StringVarP(&o.seqName, "seq", "S", "", "name of a loaded sequence to run instead of decl.element arguments")
+	f.StringVar(&o.answersFile, "answers", "", "YAML/JSON file which answers the questions asked during build and apply")
+	f.StringArrayVar(&o.cases, "case", nil, "selected case in form of decl.element=case, can be repeated")
+	f.BoolVarP(&o.yes, "yes", "y", false, "answer yes to all yes/no questions which are not in the answers file")
+	f.BoolVar(&o.ci, "non-interactive", false, "fail on any question which is not answered instead of asking for input")
+	f.BoolVarP(&o.keepGoing, "keep-going", "k", false, "build every reachable node and report all errors instead of stopping at the first one")
+	f.StringVar(&o.missingKey, "missing-key", string(gojen.MissingKeyZero), "behavior of a template which references a missing arg: zero, error or warn")
+	f.StringVar(&o.replay, "replay", "", "build id in the store directory whose recorded session is replayed")
+	f.StringVar(&o.resume, "resume", "", "build id in the store directory whose failed build is continued")
+	f.StringVar(&o.storePath, "store", ".gojen", "directory to store the build states")
+	f.StringVar(&o.commentQuote, "comment-quote", "//", "comment quote used to find the gojen anchors")
+	f.BoolVarP(&o.silent, "silent", "s", false, "do not print the build logs")
+	f.BoolVar(&o.noColor, "no-color", false, "disable colored output")
+
+	cmd.AddCommand(
+		newBuildCmd(o),
+		newApplyCmd(o),
+		newPlanCmd(o),
+		newListCmd(o),
+		newDescribeCmd(o),
+		newUndoCmd(o),
+		newValidateCmd(o),
+		newGraphCmd(o),
+	)
+
+	return cmd
+}
+
+// newBareGojen creates a gojen instance configured by flags without loading
+// any declarations.
+func (o *options) newBareGojen() (*gojen.Gojen, error) {
+	answers, err := o.parseAnswers()
+	if err != nil {
+		return nil, err
+	}
+
+	if o.replay != "" {
+		rec, err := gojen.LoadRecording(o.storePath, o.replay)
+		if err != nil {
+			return nil, err
+		}
+		o.recording = rec
+		answers = rec.Answers
+	}
+
+	missingKey, err := gojen.ParseMissingKey(o.missingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	cc := cli.C().WithColor(!o.noColor)
+	c := gojen.C().
+		SetConsoleConfig(cc).
+		SetSilent(o.silent).
+		SetStorePath(o.storePath).
+		SetCommentQuote(o.commentQuote).
+		SetInteractive(!o.ci && o.recording == nil).
+		SetKeepGoing(o.keepGoing).
+		SetMissingKey(missingKey).
+		SetAnswers(answers)
+
+	return gojen.NewWithConfig(c), nil
+}
+
+// parseAnswers merges the answers file with the answer flags.
+func (o *options) parseAnswers() (*gojen.Answers, error) {
+	a := gojen.NewAnswers()
+	if o.answersFile != "" {
+		fa, err := gojen.LoadAnswers(o.answersFile)
+		if err != nil {
+			return nil, err
+		}
+		a = fa
+	}
+
+	for _, c := range o.cases {
+		node, eName, ok := strings.Cut(c, "=")
+		if !ok || node == "" || eName == "" {
+			return nil, fmt.Errorf("invalid case '%s', expected decl.element=case", c)
+		}
+		a.Cases[node] = eName
+	}
+
+	if o.yes {
+		yes := true
+		a.ConfirmAll = &yes
+	}
+
+	return a, nil
+}
+
+// newGojen creates a gojen instance with the declarations and arguments
+// provided by flags.
+func (o *options) newGojen() (*gojen.Gojen, error) {
+	g, err := o.newBareGojen()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.LoadDecls(o.declDirs...); err != nil {
+		return nil, err
+	}
+
+	args, err := o.parseArgs()
+	if err != nil {
+		return nil, err
+	}
+	g.UpdateArgs(args)
+
+	return g, nil
+}
+
+// parseArgs merges the args file with the key=value args.
+func (o *options) parseArgs() (gojen.Args, error) {
+	args := gojen.NewArgs()
+
+	if o.argsFile != "" {
+		b, err := os.ReadFile(o.argsFile)
+		if err != nil {
+			return nil, err
+		}
+
+		fileArgs := gojen.NewArgs()
+		switch filepath.Ext(o.argsFile) {
+		case ".json":
+			err = json.Unmarshal(b, &fileArgs)
+		default:
+			err = yaml.Unmarshal(b, &fileArgs)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing args file '%s': %w", o.argsFile, err)
+		}
+		args.Merge(fileArgs)
+	}
+
+	for _, a := range o.args {
+		k, v, ok := strings.Cut(a, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid argument '%s', expected key=value", a)
+		}
+		args[k] = v
+	}
+
+	return args, nil
+}
+
+// build builds the replayed recording, resumes the failed build, or builds the
+// sequence named by the flags.
+func (o *options) build(g *gojen.Gojen, refs []string) error {
+	if o.recording != nil {
+		if len(refs) > 0 || o.seqName != "" {
+			return fmt.Errorf("decl.element arguments and --seq can not be used with --replay")
+		}
+
+		return g.Replay(o.recording)
+	}
+
+	if o.resume != "" {
+		if len(refs) > 0 || o.seqName != "" {
+			return fmt.Errorf("decl.element arguments and --seq can not be used with --resume")
+		}
+
+		return g.Resume(o.resume)
+	}
+
+	seq, err := o.newSeq(g, refs)
+	if err != nil {
+		return err
+	}
+
+	// nothing can be asked in non-interactive mode, the sequence is validated
+	// before any state is built.
+	if o.ci {
+		if err := g.Validate(seq); err != nil {
+			return err
+		}
+	}
+
+	return g.Build(seq)
+}
+
+// newSeq returns the loaded sequence named by the seq flag, or creates a
+// sequence from the positional arguments.
+func (o *options) newSeq(g *gojen.Gojen, refs []string) (*gojen.Seq, error) {
+	if o.seqName == "" {
+		return parseSeq(refs)
+	}
+
+	if len(refs) > 0 {
+		return nil, fmt.Errorf("decl.element arguments can not be used with --seq")
+	}
+
+	return g.Seq(o.seqName)
+}
+
+// parseSeq creates a sequence from the positional arguments in form of
+// decl.element.
+func parseSeq(refs []string) (*gojen.Seq, error) {
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("at least one element in form of decl.element or --seq is required")
+	}
+
+	var seq *gojen.Seq
+	for _, ref := range refs {
+		dName, eName, ok := strings.Cut(ref, ".")
+		if !ok || dName == "" || eName == "" {
+			return nil, fmt.Errorf("invalid element '%s', expected decl.element", ref)
+		}
+
+		if seq == nil {
+			seq = gojen.NewSeq(dName, eName)
+			continue
+		}
+		seq = seq.Append(dName, eName)
+	}
+
+	return seq, nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newUndoCmd(o *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "undo [build]",
+		Short: "Revert the files touched by an applied build",
+		Long: `Revert the files touched by an applied build.
+
+The original content of the modified files is restored and the created files
+are deleted. If no build is given, the latest applied build is undone.`,
+		Example: `  gojen undo
+  gojen undo 20240102150405`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, ids []string) error {
+			buildID := ""
+			if len(ids) > 0 {
+				buildID = ids[0]
+			}
+
+			g, err := o.newBareGojen()
+			if err != nil {
+				return err
+			}
+
+			return g.Undo(buildID)
+		},
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newValidateCmd(o *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate [decl.element...]",
+		Short: "Validate every branch of a sequence without building it",
+		Long: `Validate every branch of a sequence without building it.
+
+All unknown declarations, elements, strategies and cases, and the required
+arguments which are not given are reported at once.`,
+		Example: `  gojen validate -d decls -a Domain=customer model.initModelFile
+  gojen validate -d decls --seq crud`,
+		RunE: func(cmd *cobra.Command, refs []string) error {
+			g, err := o.newGojen()
+			if err != nil {
+				return err
+			}
+
+			seq, err := o.newSeq(g, refs)
+			if err != nil {
+				return err
+			}
+
+			if err := g.Validate(seq); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "The sequence is valid.")
+			return nil
+		},
+	}
+}
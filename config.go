@@ -1,12 +1,47 @@
 package gojen
 
 import (
+	"fmt"
+
 	"github.com/cirius-go/gojen/lib/cli"
 	"github.com/cirius-go/gojen/lib/filemanager"
 	"github.com/cirius-go/gojen/lib/pipeline"
 	"github.com/cirius-go/gojen/util"
 )
 
+// MissingKey is the behavior of a template which references an arg that is
+// not provided.
+type MissingKey string
+
+const (
+	// MissingKeyZero renders the missing arg as its zero value.
+	MissingKeyZero MissingKey = "zero"
+	// MissingKeyError fails the build.
+	MissingKeyError MissingKey = "error"
+	// MissingKeyWarn renders the missing arg as its zero value, warns and
+	// lists the missing args in the state.
+	MissingKeyWarn MissingKey = "warn"
+)
+
+// IsValid reports whether the missing key is one of the behaviors.
+func (m MissingKey) IsValid() bool {
+	switch m {
+	case MissingKeyZero, MissingKeyError, MissingKeyWarn:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseMissingKey converts a string to a MissingKey.
+func ParseMissingKey(name string) (MissingKey, error) {
+	if m := MissingKey(name); m.IsValid() {
+		return m, nil
+	}
+
+	return "", fmt.Errorf("invalid missing key '%s', expected zero, error or warn", name)
+}
+
 // config is a struct that holds the configuration for the Gojen instance.
 type config struct {
 	console              *cli.Config
@@ -17,6 +52,10 @@ type config struct {
 	commentQuote         string
 	storePath            string
 	ignoreComparingLines util.MapExisting[string]
+	interactive          bool
+	answers              *Answers
+	keepGoing            bool
+	missingKey           MissingKey
 }
 
 // SetCommentQuote sets the commentQuote field of the Config struct.
@@ -37,6 +76,38 @@ func (c *config) SetStorePath(storePath string) *config {
 	return c
 }
 
+// SetInteractive sets the interactive field of the Config struct. In
+// non-interactive mode every question must be answered by the answers,
+// otherwise the build fails instead of waiting for the input.
+func (c *config) SetInteractive(interactive bool) *config {
+	c.interactive = interactive
+	return c
+}
+
+// SetAnswers sets the answers field of the Config struct.
+func (c *config) SetAnswers(answers *Answers) *config {
+	c.answers = answers
+	return c
+}
+
+// SetKeepGoing sets the keepGoing field of the Config struct. In keep-going
+// mode the build does not stop at the first failing node, it builds every
+// reachable node and returns all errors at once. Nothing can be applied while
+// there are errors.
+func (c *config) SetKeepGoing(keepGoing bool) *config {
+	c.keepGoing = keepGoing
+	return c
+}
+
+// SetMissingKey sets the missingKey field of the Config struct. The
+// conditions always render a missing arg as its zero value. The build fails if
+// the missing key is not one of the behaviors, use ParseMissingKey to check an
+// input.
+func (c *config) SetMissingKey(missingKey MissingKey) *config {
+	c.missingKey = missingKey
+	return c
+}
+
 // IgnoreComparingLine adds a new ignoreCompareLineWith to the Config struct.
 func (c *config) IgnoreComparingLine(lines ...string) *config {
 	c.ignoreComparingLines.Add(lines...)
@@ -74,5 +145,7 @@ func C() *config {
 		commentQuote:         "//",
 		storePath:            ".gojen",
 		ignoreComparingLines: make(util.MapExisting[string]),
+		interactive:          true,
+		missingKey:           MissingKeyZero,
 	}
 }
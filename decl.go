@@ -2,16 +2,18 @@ package gojen
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 )
 
 // Strategy is a type that represents the strategy for setting a template.
 // ENUM(init,prepend_at_head,prepend,append,append_at_pos,edit)
 // init: Create file and set content by template. If this file exists, ignore.
 // prepend_at_head: Prepend content at head of file.
-// prepend: Prepend at anchor position.
-// append: Append at anchor position.
+// prepend: Prepend before anchor position (+gojen:prepend=<alias>).
+// append: Append after anchor position (+gojen:append=<alias>).
 // append_at_pos: append at the end of file.
-// edit: Edit at anchor position.
+// edit: Replace content between anchor (+gojen:edit=<alias>) and (+gojen:end=<alias>).
 // output: Output of a seq.
 //
 //go:generate go-enum -f=$GOFILE --marshal --names --values
@@ -19,20 +21,28 @@ type Strategy string
 
 type (
 	Output struct {
-		Path     string `json:"path" yaml:"path"`
-		Template string `json:"template" yaml:"template"`
+		Path         string `json:"path" yaml:"path"`
+		Template     string `json:"template" yaml:"template"`
+		TemplateFile string `json:"template_file" yaml:"template_file"` // file of the template, relative to the declaration file.
+		source       string // file which the template is loaded from.
 	}
 
 	// T represents a element.
 	T struct {
-		Path     string             `json:"path" yaml:"path" validate:"required"`
-		Name     string             `json:"name" yaml:"name" validate:"required"`
-		Alias    string             `json:"alias" yaml:"alias"`
-		Require  []string           `json:"require" yaml:"require"`
-		Args     Args               `json:"args" yaml:"args" validate:"required"`
-		Template string             `json:"template" yaml:"template" validate:"required"`
-		Strategy Strategy           `json:"strategy" yaml:"strategy" validate:"required"`
-		Output   map[string]*Output `json:"output" yaml:"output"`
+		Path         string             `json:"path" yaml:"path" validate:"required"`
+		Name         string             `json:"name" yaml:"name" validate:"required"`
+		Alias        string             `json:"alias" yaml:"alias"`
+		Require      []string           `json:"require" yaml:"require"`
+		Params       Params             `json:"params" yaml:"params"`
+		Args         Args               `json:"args" yaml:"args" validate:"required"`
+		Template     string             `json:"template" yaml:"template" validate:"required"`
+		TemplateFile string             `json:"template_file" yaml:"template_file"` // file of the template, relative to the declaration file.
+		Strategy     Strategy           `json:"strategy" yaml:"strategy" validate:"required"`
+		Output       map[string]*Output `json:"output" yaml:"output"`
+		If           string             `json:"if" yaml:"if"`         // condition, the element is skipped if it is false.
+		Delims       []string           `json:"delims" yaml:"delims"` // left and right delimiters, override the declaration.
+		source       string             // file which the template is loaded from.
+		line         int                // lines of the source before the template.
 	}
 
 	// D represents a group of declaration for templates.
@@ -40,10 +50,13 @@ type (
 		Path        string   `json:"path" yaml:"path"`
 		Name        string   `json:"name" yaml:"name"`
 		Require     []string `json:"require" yaml:"require"`
+		Params      Params   `json:"params" yaml:"params"`
 		Args        Args     `json:"args" yaml:"args"`
 		Templates   []*T     `json:"elements" yaml:"elements"`
 		Description string   `json:"description" yaml:"description"`
+		Delims      []string `json:"delims" yaml:"delims"` // left and right delimiters of the templates, default '{{' and '}}'.
 		selected    string
+		source      string // file which the declaration is loaded from.
 	}
 
 	// M represents a map of template definitions.
@@ -51,12 +64,24 @@ type (
 )
 
 func (e *T) Validate() error {
-	if e.Path == "" {
+	return e.validate("")
+}
+
+// validate validates the element, whose path is inherited from its
+// declaration if it is empty.
+func (e *T) validate(inheritedPath string) error {
+	if e.Path == "" && inheritedPath == "" {
 		return fmt.Errorf("path is required")
 	}
 	if e.Name == "" {
 		return fmt.Errorf("name is required")
 	}
+	if err := e.Params.Validate(); err != nil {
+		return fmt.Errorf("element '%s': %w", e.Name, err)
+	}
+	if err := validateDelims(e.Delims); err != nil {
+		return fmt.Errorf("element '%s': %w", e.Name, err)
+	}
 	return nil
 }
 
@@ -67,9 +92,16 @@ func (d *D) Validate() error {
 	if len(d.Templates) == 0 {
 		return fmt.Errorf("elements are required")
 	}
+	if err := d.Params.Validate(); err != nil {
+		return err
+	}
+	if err := validateDelims(d.Delims); err != nil {
+		return err
+	}
 
 	for _, e := range d.Templates {
-		if err := e.Validate(); err != nil {
+		// element inherits the path of the declaration.
+		if err := e.validate(d.Path); err != nil {
 			return err
 		}
 	}
@@ -77,6 +109,67 @@ func (d *D) Validate() error {
 	return nil
 }
 
+// Source returns the file which the declaration is loaded from, it is empty
+// if the declaration is not loaded from a file.
+func (d *D) Source() string {
+	return d.source
+}
+
+// LoadTemplateFiles reads the template files of the elements and their
+// outputs into their templates. The relative paths are resolved from dir,
+// which is the directory of the declaration file when it is loaded by LoadDir.
+// The template files which are already loaded are skipped.
+func (d *D) LoadTemplateFiles(dir string) error {
+	for _, e := range d.Templates {
+		if e.TemplateFile != "" && e.source == "" {
+			if e.Template != "" {
+				return fmt.Errorf("element '%s': template and template_file can not be both set", e.Name)
+			}
+
+			path, content, err := readTemplateFile(dir, e.TemplateFile)
+			if err != nil {
+				return fmt.Errorf("element '%s': %w", e.Name, err)
+			}
+			e.Template = content
+			e.source = path
+		}
+
+		for k, o := range e.Output {
+			if o.TemplateFile == "" || o.source != "" {
+				continue
+			}
+			if o.Template != "" {
+				return fmt.Errorf("output '%s' of element '%s': template and template_file can not be both set", k, e.Name)
+			}
+
+			path, content, err := readTemplateFile(dir, o.TemplateFile)
+			if err != nil {
+				return fmt.Errorf("output '%s' of element '%s': %w", k, e.Name, err)
+			}
+			o.Template = content
+			o.source = path
+		}
+	}
+
+	return nil
+}
+
+// readTemplateFile reads the template file, a relative path is resolved from
+// dir.
+func readTemplateFile(dir, file string) (string, string, error) {
+	path := file
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, file)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("error reading template file: %w", err)
+	}
+
+	return path, string(b), nil
+}
+
 // GetElements returns the element with the given name.
 func (d *D) GetElements(name string) *T {
 	for _, el := range d.Templates {
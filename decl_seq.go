@@ -0,0 +1,221 @@
+package gojen
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+type (
+	// Step represents a step of a declarative sequence. The elements of the
+	// step are appended in order, once per item of With. If Cases is set, the
+	// step selects between the elements of the cases after its elements are
+	// appended, each case continues with its own steps. If Include is set, the
+	// step appends the included sequence instead, once per item of With which
+	// overrides the args of the included sequence.
+	Step struct {
+		Include     string             `json:"include" yaml:"include"` // name of the included sequence.
+		DName       string             `json:"d_name" yaml:"d_name"`
+		ENames      []string           `json:"e_names" yaml:"e_names"`
+		With        []Args             `json:"with" yaml:"with"`
+		Forward     []string           `json:"forward_args" yaml:"forward_args"`
+		Cases       map[string][]*Step `json:"cases" yaml:"cases"`
+		DefaultCase string             `json:"default_case" yaml:"default_case"` // case selected if no case is given.
+		MultiSelect bool               `json:"multi_select" yaml:"multi_select"` // allow selecting several cases.
+		SelectArg   string             `json:"select_arg" yaml:"select_arg"`     // arg whose value selects the cases.
+		Loop        string             `json:"loop" yaml:"loop"`                 // list arg which each element is built for each item of.
+		LoopAs      string             `json:"loop_as" yaml:"loop_as"`           // temp arg which each item is bound to.
+		If          string             `json:"if" yaml:"if"`                     // condition of each element, it is skipped if false.
+	}
+
+	// S represents a declarative sequence, which can be loaded from files next
+	// to the declarations.
+	S struct {
+		Name        string  `json:"name" yaml:"name"`
+		Description string  `json:"description" yaml:"description"`
+		Steps       []*Step `json:"steps" yaml:"steps"`
+	}
+)
+
+func (st *Step) Validate() error {
+	if st.Include != "" {
+		if len(st.ENames) > 0 || len(st.Cases) > 0 {
+			return fmt.Errorf("include '%s' can not be combined with e_names or cases", st.Include)
+		}
+		return nil
+	}
+	if st.DName == "" {
+		return fmt.Errorf("d_name is required")
+	}
+	if len(st.ENames) == 0 && len(st.Cases) == 0 {
+		return fmt.Errorf("e_names or cases are required for step '%s'", st.DName)
+	}
+	if st.Loop != "" && st.LoopAs == "" {
+		return fmt.Errorf("loop_as is required for the loop of step '%s'", st.DName)
+	}
+
+	for _, steps := range st.Cases {
+		for _, c := range steps {
+			if err := c.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *S) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("steps are required")
+	}
+	if len(s.Steps[0].ENames) == 0 && s.Steps[0].Include == "" {
+		return fmt.Errorf("e_names or include are required for the first step")
+	}
+
+	for _, st := range s.Steps {
+		if err := st.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Seq creates the sequence from the declarative steps. It can not include
+// other sequences, use SeqWith instead.
+func (s *S) Seq() (*Seq, error) {
+	return s.SeqWith(nil)
+}
+
+// SeqWith creates the sequence from the declarative steps. The included
+// sequences are found by name with find.
+func (s *S) SeqWith(find func(name string) *S) (*Seq, error) {
+	return s.seq(find, nil)
+}
+
+func (s *S) seq(find func(name string) *S, including []string) (*Seq, error) {
+	if err := s.Validate(); err != nil {
+		return nil, fmt.Errorf("error validating sequence '%s': %w", s.Name, err)
+	}
+	if slices.Contains(including, s.Name) {
+		return nil, fmt.Errorf("sequence '%s' includes itself: %s", s.Name, strings.Join(append(including, s.Name), " -> "))
+	}
+
+	return appendSteps(nil, s.Steps, find, append(slices.Clone(including), s.Name))
+}
+
+// appendSteps appends the steps to cur. If cur is nil, a new sequence is
+// created by the first step.
+func appendSteps(cur *Seq, steps []*Step, find func(name string) *S, including []string) (*Seq, error) {
+	var err error
+	for _, st := range steps {
+		if cur, err = st.append(cur, find, including); err != nil {
+			return nil, err
+		}
+	}
+
+	return cur, nil
+}
+
+func (st *Step) append(cur *Seq, find func(name string) *S, including []string) (*Seq, error) {
+	if st.Include != "" {
+		return st.include(cur, find, including)
+	}
+
+	if len(st.ENames) > 0 {
+		chainArgs := st.With
+		if len(chainArgs) == 0 {
+			chainArgs = []Args{nil}
+		}
+
+		for _, args := range chainArgs {
+			for _, eName := range st.ENames {
+				if cur == nil {
+					cur = NewSeq(st.DName, eName)
+				} else {
+					cur = cur.Append(st.DName, eName)
+				}
+				if st.Loop != "" {
+					cur.Loop(st.Loop, st.LoopAs)
+				}
+				cur.If(st.If)
+			}
+			cur.TempArgs = args
+		}
+	}
+
+	if cur == nil {
+		return nil, fmt.Errorf("step '%s' has no previous element to continue from", st.DName)
+	}
+
+	cur.Forward(st.Forward...)
+
+	if len(st.Cases) == 0 {
+		return cur, nil
+	}
+
+	eNames := make([]string, 0, len(st.Cases))
+	for eName := range st.Cases {
+		eNames = append(eNames, eName)
+	}
+	sort.Strings(eNames)
+
+	var err error
+	cur.MultiSelect = st.MultiSelect
+	cur.Default(st.DefaultCase)
+	cur.SelectBy(st.SelectArg, st.DName, eNames, func(ss SeqSwitcher) {
+		for _, eName := range eNames {
+			ss.When(eName, func(c *Seq) *Seq {
+				if err != nil {
+					return c
+				}
+
+				var last *Seq
+				if last, err = appendSteps(c, st.Cases[eName], find, including); err != nil {
+					return c
+				}
+				return last
+			})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cur, nil
+}
+
+// include appends the included sequence to cur, once per item of With.
+func (st *Step) include(cur *Seq, find func(name string) *S, including []string) (*Seq, error) {
+	var sd *S
+	if find != nil {
+		sd = find(st.Include)
+	}
+	if sd == nil {
+		return nil, fmt.Errorf("included sequence '%s' not found", st.Include)
+	}
+
+	sub, err := sd.seq(find, including)
+	if err != nil {
+		return nil, err
+	}
+
+	chainArgs := st.With
+	if len(chainArgs) == 0 {
+		chainArgs = []Args{nil}
+	}
+	for _, args := range chainArgs {
+		if cur == nil {
+			cur = sub.copyWith(args)
+			continue
+		}
+		cur = cur.Include(sub, args)
+	}
+
+	return cur, nil
+}
@@ -0,0 +1,64 @@
+package gojen
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// delims are the action delimiters of the templates of an element.
+type delims struct {
+	left, right string
+}
+
+// defaultDelims are the delimiters of text/template.
+var defaultDelims = delims{left: "{{", right: "}}"}
+
+// templateDelims returns the delimiters of the element, which override the
+// delimiters of the declaration.
+func templateDelims(decl *D, declElem *T) delims {
+	for _, d := range [][]string{declElem.Delims, decl.Delims} {
+		if len(d) == 2 {
+			return delims{left: d[0], right: d[1]}
+		}
+	}
+
+	return defaultDelims
+}
+
+// validateDelims validates the delimiters of a declaration or an element.
+func validateDelims(d []string) error {
+	if len(d) == 0 {
+		return nil
+	}
+	if len(d) != 2 || d[0] == "" || d[1] == "" {
+		return fmt.Errorf("delims must be a pair of left and right delimiters")
+	}
+
+	return nil
+}
+
+// escapeRaw replaces the raw blocks of the template, '<left>raw<right>' until
+// '<left>/raw<right>', with actions which print their content verbatim. A
+// comment keeps the line numbers of the rest of the template.
+func (d delims) escapeRaw(tmpl string) string {
+	if !strings.Contains(tmpl, "raw") {
+		return tmpl
+	}
+
+	var (
+		l  = regexp.QuoteMeta(d.left)
+		r  = regexp.QuoteMeta(d.right)
+		re = regexp.MustCompile(`(?s)` + l + `\s*raw\s*` + r + `(.*?)` + l + `\s*/raw\s*` + r)
+	)
+
+	return re.ReplaceAllStringFunc(tmpl, func(block string) string {
+		content := re.FindStringSubmatch(block)[1]
+		res := d.left + " " + strconv.Quote(content) + " " + d.right
+		if n := strings.Count(content, "\n"); n > 0 {
+			res += d.left + "/*" + strings.Repeat("\n", n) + "*/" + d.right
+		}
+		return res
+	})
+}
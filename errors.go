@@ -0,0 +1,279 @@
+package gojen
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cirius-go/gojen/util"
+)
+
+type (
+	// ErrDeclNotFound is returned when a sequence refers to an unknown
+	// declaration.
+	ErrDeclNotFound struct {
+		DName string
+	}
+
+	// ErrElementNotFound is returned when a sequence refers to an unknown
+	// element of a declaration.
+	ErrElementNotFound struct {
+		DName string
+		EName string
+	}
+
+	// ErrSeqNotFound is returned when a sequence definition is not loaded.
+	ErrSeqNotFound struct {
+		Name string
+	}
+
+	// ErrMissingArg is returned when required arguments of an element are not
+	// given and can not be asked.
+	ErrMissingArg struct {
+		DName string
+		EName string
+		Args  []string
+	}
+
+	// ErrInvalidArg is returned when an argument does not match its param.
+	ErrInvalidArg struct {
+		DName string
+		EName string
+		Err   error
+	}
+
+	// ErrUnknownStrategy is returned when an element has an unknown strategy.
+	ErrUnknownStrategy struct {
+		DName    string
+		EName    string
+		Strategy Strategy
+	}
+
+	// ErrInvalidSeq is returned by Validate with all problems of a sequence.
+	// The problems can be checked by errors.Is/As.
+	ErrInvalidSeq struct {
+		DName string // root of the sequence.
+		EName string
+		Errs  []error
+	}
+
+	// ErrNode is an error of building a node of a sequence.
+	ErrNode struct {
+		DName string
+		EName string
+		Err   error
+	}
+
+	// ErrBuild is returned by Build in keep-going mode with the errors of all
+	// failing nodes. The errors can be checked by errors.Is/As.
+	ErrBuild struct {
+		Errs []*ErrNode
+	}
+
+	// ErrTemplate is returned when a template of an element can not be parsed
+	// or executed. Line and Column are the position in the source, or in the
+	// template if it has no own file, starting from 1, they are 0 if unknown.
+	ErrTemplate struct {
+		DName    string
+		EName    string
+		Source   string // file which the declaration is loaded from.
+		Name     string // which template of the element, e.g. content or path.
+		Template string
+		Line     int
+		Column   int
+		Err      error
+
+		msg    string // message of Err without the position.
+		offset int    // lines of the source before the template.
+	}
+)
+
+func (e *ErrDeclNotFound) Error() string {
+	return fmt.Sprintf("Declaration '%s' not found", e.DName)
+}
+
+// Is reports whether target is an ErrDeclNotFound of the same declaration, or
+// of any declaration if its name is empty.
+func (e *ErrDeclNotFound) Is(target error) bool {
+	t, ok := target.(*ErrDeclNotFound)
+	return ok && (t.DName == "" || t.DName == e.DName)
+}
+
+func (e *ErrElementNotFound) Error() string {
+	return fmt.Sprintf("Element '%s' not found in declaration '%s'", e.EName, e.DName)
+}
+
+// Is reports whether target is an ErrElementNotFound of the same element, or
+// of any element if its names are empty.
+func (e *ErrElementNotFound) Is(target error) bool {
+	t, ok := target.(*ErrElementNotFound)
+	return ok && matchNames(t.DName, t.EName, e.DName, e.EName)
+}
+
+func (e *ErrSeqNotFound) Error() string {
+	return fmt.Sprintf("Sequence '%s' not found", e.Name)
+}
+
+// Is reports whether target is an ErrSeqNotFound of the same sequence, or of
+// any sequence if its name is empty.
+func (e *ErrSeqNotFound) Is(target error) bool {
+	t, ok := target.(*ErrSeqNotFound)
+	return ok && (t.Name == "" || t.Name == e.Name)
+}
+
+func (e *ErrMissingArg) Error() string {
+	return fmt.Sprintf("missing required arguments [%s] of '%s.%s'", strings.Join(e.Args, ", "), e.DName, e.EName)
+}
+
+// Is reports whether target is an ErrMissingArg of the same element, or of
+// any element if its names are empty.
+func (e *ErrMissingArg) Is(target error) bool {
+	t, ok := target.(*ErrMissingArg)
+	return ok && matchNames(t.DName, t.EName, e.DName, e.EName)
+}
+
+func (e *ErrInvalidArg) Error() string {
+	return fmt.Sprintf("invalid arguments of '%s.%s': %s", e.DName, e.EName, e.Err)
+}
+
+func (e *ErrInvalidArg) Unwrap() error {
+	return e.Err
+}
+
+func (e *ErrUnknownStrategy) Error() string {
+	return fmt.Sprintf("unknown strategy '%s' of element '%s.%s'", e.Strategy, e.DName, e.EName)
+}
+
+func (e *ErrInvalidSeq) Error() string {
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "sequence '%s.%s' is invalid:", e.DName, e.EName)
+	for _, err := range e.Errs {
+		fmt.Fprintf(&b, "\n  - %s", err)
+	}
+
+	return b.String()
+}
+
+func (e *ErrInvalidSeq) Unwrap() []error {
+	return e.Errs
+}
+
+func (e *ErrNode) Error() string {
+	return fmt.Sprintf("%s.%s: %s", e.DName, e.EName, e.Err)
+}
+
+func (e *ErrNode) Unwrap() error {
+	return e.Err
+}
+
+func (e *ErrBuild) Error() string {
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "build failed with %d errors:", len(e.Errs))
+	for _, err := range e.Errs {
+		fmt.Fprintf(&b, "\n  - %s", err)
+	}
+
+	return b.String()
+}
+
+func (e *ErrBuild) Unwrap() []error {
+	res := make([]error, 0, len(e.Errs))
+	for _, err := range e.Errs {
+		res = append(res, err)
+	}
+
+	return res
+}
+
+func (e *ErrTemplate) Error() string {
+	pos := ""
+	if e.Line > 0 {
+		pos = fmt.Sprintf("line %d", e.Line)
+		if e.Column > 0 {
+			pos += fmt.Sprintf(", column %d", e.Column)
+		}
+	}
+
+	loc := strings.Join(slices.DeleteFunc([]string{e.Source, pos}, func(s string) bool { return s == "" }), ", ")
+	if loc != "" {
+		loc = " (" + loc + ")"
+	}
+
+	return fmt.Sprintf("invalid %s of '%s.%s'%s: %s", e.Name, e.DName, e.EName, loc, util.IfValue(e.Err.Error(), e.msg))
+}
+
+func (e *ErrTemplate) Unwrap() error {
+	return e.Err
+}
+
+// Snippet returns the lines of the template around the error line, the error
+// line is marked and followed by a caret at the error column.
+func (e *ErrTemplate) Snippet(context int) string {
+	if e.Line <= 0 {
+		return ""
+	}
+
+	lines := strings.Split(e.Template, "\n")
+	if e.Line-e.offset > len(lines) {
+		return ""
+	}
+
+	var (
+		b     = strings.Builder{}
+		start = max(e.Line-context, e.offset+1)
+		end   = min(e.Line+context, e.offset+len(lines))
+		width = len(strconv.Itoa(end))
+	)
+	for i := start; i <= end; i++ {
+		marker := " "
+		if i == e.Line {
+			marker = ">"
+		}
+		fmt.Fprintf(&b, "%s %*d | %s\n", marker, width, i, lines[i-1-e.offset])
+		if i == e.Line && e.Column > 0 {
+			fmt.Fprintf(&b, "  %s | %s^\n", strings.Repeat(" ", width), strings.Repeat(" ", e.Column-1))
+		}
+	}
+
+	return b.String()
+}
+
+// templateErrPattern matches the position of a text/template error, e.g.
+// 'template: content:3:14: executing ...' or 'template: content:3: unexpected ...'.
+var templateErrPattern = regexp.MustCompile(`^template: [^:]*:(\d+)(?::(\d+))?: (.*)$`)
+
+// newTemplateError creates an ErrTemplate of the element from an error of
+// text/template.
+func newTemplateError(decl *D, declElem *T, name, tmpl string, err error) *ErrTemplate {
+	e := &ErrTemplate{
+		DName:    decl.Name,
+		EName:    declElem.Name,
+		Source:   decl.source,
+		Name:     name,
+		Template: tmpl,
+		Err:      err,
+	}
+
+	// the content may be loaded from its own file, after a front matter.
+	if name == "content" && declElem.source != "" {
+		e.Source = declElem.source
+		e.offset = declElem.line
+	}
+
+	if m := templateErrPattern.FindStringSubmatch(err.Error()); m != nil {
+		e.Line, _ = strconv.Atoi(m[1])
+		e.Column, _ = strconv.Atoi(m[2])
+		e.msg = m[3]
+		e.Line += e.offset
+	}
+
+	return e
+}
+
+// matchNames reports whether the target names are empty or equal to the
+// names.
+func matchNames(tDName, tEName, dName, eName string) bool {
+	return (tDName == "" || tDName == dName) && (tEName == "" || tEName == eName)
+}
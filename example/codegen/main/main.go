@@ -42,7 +42,11 @@ func main() {
 	})
 	c := gojen.C().SetPipelineConfig(pc)
 	g := gojen.NewWithConfig(c)
-	g.UpdateArgs(gojen.Args{"Domain": "customer", "BaseAPI": "cms"})
+	g.UpdateArgs(gojen.Args{
+		"Domain":  "customer",
+		"BaseAPI": "cms",
+		"Methods": []string{"get", "list", "create", "update", "delete"},
+	})
 	g.SetDecls(decl.APIDecl)
 
 	// crud
@@ -61,13 +65,7 @@ func main() {
 			{"Method": "delete"},
 		}, "dto", "createDto").
 		Append("svc", "initIntfFile", "createIntf", "initSvcFile").
-		AppendWiths([]gojen.Args{
-			{"Method": "get"},
-			{"Method": "list"},
-			{"Method": "create"},
-			{"Method": "update"},
-			{"Method": "delete"},
-		}, "svc", "createHandler").
+		AppendLoop("Methods", "Method", "svc", "createHandler").
 		Append("api", "initIntfFile", "createIntf", "initApiFile").
 		AppendWiths([]gojen.Args{
 			{"Method": "get", "HTTPMethod": "get", "Slug": "/:id"},
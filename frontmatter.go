@@ -0,0 +1,66 @@
+package gojen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// frontMatterDelim opens and closes the header of a front matter file.
+const frontMatterDelim = "---"
+
+// frontMatter is the header of a template file which declares one element.
+// The fields of the element are set as in the elements of a declaration.
+type frontMatter struct {
+	Decl string `yaml:"decl"` // declaration of the element, default the name of the directory.
+	T    `yaml:",inline"`
+}
+
+// isFrontMatterFile reports whether the file declares an element in its
+// front matter, which is named as <name>.gojen.tmpl. Other template files,
+// e.g. the template_file of an element, are not parsed.
+func isFrontMatterFile(name string) bool {
+	return filepath.Ext(name) == ".tmpl" && strings.HasSuffix(strings.TrimSuffix(name, ".tmpl"), ".gojen")
+}
+
+// parseFrontMatter parses a template file with a YAML front matter header
+// followed by the template of the element.
+func parseFrontMatter(r io.Reader) (*frontMatter, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.SplitAfter(string(bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n"))), "\n")
+	if strings.TrimSpace(lines[0]) != frontMatterDelim {
+		return nil, fmt.Errorf("front matter is required, the file must start with '%s'", frontMatterDelim)
+	}
+
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], " \t\n") == frontMatterDelim {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return nil, fmt.Errorf("front matter is not closed by '%s'", frontMatterDelim)
+	}
+
+	fm := &frontMatter{}
+	if err := yaml.Unmarshal([]byte(strings.Join(lines[1:end], "")), fm); err != nil {
+		return nil, fmt.Errorf("error parsing front matter: %w", err)
+	}
+	if fm.Template != "" || fm.TemplateFile != "" {
+		return nil, fmt.Errorf("element '%s': the template is the content after the front matter", fm.Name)
+	}
+
+	fm.Template = strings.Join(lines[end+1:], "")
+	fm.line = end + 1
+
+	return fm, nil
+}
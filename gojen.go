@@ -1,12 +1,13 @@
 package gojen
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
-	"strconv"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
 	"text/template"
 	"time"
@@ -27,7 +28,11 @@ type Gojen struct {
 	c ConsoleManager
 
 	// cached variable during build.
+	buildID       string
 	localStateDir string
+	recording     *Recording
+	resumed       []*State
+	resumedNodes  []string
 	Err           error
 	ModifiedFiles util.MapExisting[string]
 }
@@ -61,14 +66,24 @@ func NewWithConfig(cfg *config) *Gojen {
 		f:             f,
 		s:             s,
 		c:             c,
+		buildID:       bNum,
 		localStateDir: localStateDir,
+		recording:     &Recording{Answers: NewAnswers()},
 		ModifiedFiles: make(util.MapExisting[string]),
 	}
+	s.confirm = g.confirm
 
 	return g
 }
 
-// LoadDecls loads the declarations from the given directory.
+// BuildID returns the id of the build, which is the name of the build
+// directory in the store path.
+func (g *Gojen) BuildID() string {
+	return g.buildID
+}
+
+// LoadDecls loads the declarations and the sequences from the given
+// directories.
 func (g *Gojen) LoadDecls(dirPaths ...string) error {
 	for _, dirPath := range dirPaths {
 		if err := g.s.LoadDir(dirPath); err != nil {
@@ -85,19 +100,70 @@ func (g *Gojen) SetDecls(decls ...*D) {
 	}
 }
 
+// Decls returns all loaded declarations sorted by name.
+func (g *Gojen) Decls() []*D {
+	return g.s.GetDecls()
+}
+
+// Decl returns the declaration with the given name.
+func (g *Gojen) Decl(name string) *D {
+	return g.s.GetDecl(name)
+}
+
+// Seqs returns all loaded sequence definitions sorted by name.
+func (g *Gojen) Seqs() []*S {
+	return g.s.GetSeqs()
+}
+
+// Seq creates the sequence from the loaded sequence definition with the given
+// name.
+func (g *Gojen) Seq(name string) (*Seq, error) {
+	sd := g.s.GetSeq(name)
+	if sd == nil {
+		return nil, &ErrSeqNotFound{Name: name}
+	}
+
+	return sd.SeqWith(g.s.GetSeq)
+}
+
+// SetSeqs stores the given sequence definitions.
+func (g *Gojen) SetSeqs(seqs ...*S) {
+	for _, s := range seqs {
+		g.s.SetSeq(s)
+	}
+}
+
+// States returns the built states which are waiting to be applied.
+func (g *Gojen) States() []*State {
+	return g.s.GetStates()
+}
+
 func (g *Gojen) UpdateArgs(args Args) {
 	g.s.UpdateArgs(args)
 }
 
 // parseTemplate creates, executes a template and returns the result as a string.
-func (g *Gojen) parseTemplate(args map[string]any, name string, templateString string) (string, error) {
+// A missing arg fails the template only in MissingKeyError mode.
+func (g *Gojen) parseTemplate(args map[string]any, name string, templateString string, dl delims) (string, error) {
+	missingKey := "zero"
+	if g.cfg.missingKey == MissingKeyError {
+		missingKey = "error"
+	}
+
+	return g.execTemplate(args, name, templateString, missingKey, dl)
+}
+
+// execTemplate creates, executes a template with the missingkey option and the
+// delimiters. The raw blocks are printed verbatim.
+func (g *Gojen) execTemplate(args map[string]any, name, templateString, missingKey string, dl delims) (string, error) {
 	pipelineFns := g.p.GetFuncs()
 
 	t, err := template.
 		New(name).
+		Delims(dl.left, dl.right).
 		Funcs(pipelineFns).
-		Option("missingkey=zero").
-		Parse(templateString)
+		Option("missingkey=" + missingKey).
+		Parse(dl.escapeRaw(templateString))
 
 	if err != nil {
 		return "", err
@@ -111,40 +177,157 @@ func (g *Gojen) parseTemplate(args map[string]any, name string, templateString s
 	return w.String(), nil
 }
 
-func (g *Gojen) build(seq *Seq, i *int) error {
+// missingKeyPattern matches the error of a template which references a
+// missing key with missingkey=error.
+var missingKeyPattern = regexp.MustCompile(`map has no entry for key "([^"]+)"`)
+
+// missingArgs returns the args which are referenced by the template but not
+// provided. Each missing arg is added as nil, then the template is executed
+// again to find the next one.
+func (g *Gojen) missingArgs(args Args, templateString string, dl delims) []string {
+	var (
+		res   = []string{}
+		clone = args.Clone()
+	)
+	for {
+		_, err := g.execTemplate(clone, "missing", templateString, "error", dl)
+		if err == nil {
+			return res
+		}
+
+		m := missingKeyPattern.FindStringSubmatch(err.Error())
+		if m == nil {
+			return res
+		}
+		if _, ok := clone[m[1]]; ok {
+			// a key of a nested map is missing.
+			return res
+		}
+
+		clone[m[1]] = nil
+		res = append(res, m[1])
+	}
+}
+
+// condTemplate returns the template of the condition, which is a template
+// expression with or without the delimiters.
+func condTemplate(cond string, dl delims) string {
+	if strings.TrimSpace(cond) == "" || strings.Contains(cond, dl.left) {
+		return cond
+	}
+
+	return dl.left + " " + cond + " " + dl.right
+}
+
+// evalCond evaluates the condition against the args. It is false if it
+// renders to an empty value, 'false' or '0'.
+func (g *Gojen) evalCond(args Args, cond string, dl delims) (bool, error) {
+	cond = condTemplate(cond, dl)
+	if strings.TrimSpace(cond) == "" {
+		return true, nil
+	}
+
+	res, err := g.execTemplate(args, "if", cond, "zero", dl)
+	if err != nil {
+		return false, err
+	}
+
+	switch strings.TrimSpace(res) {
+	case "", "false", "0", "<no value>", "[]", "map[]":
+		return false, nil
+	default:
+		return true, nil
+	}
+}
+
+// condition is a condition with the delimiters which it is evaluated with.
+type condition struct {
+	cond string
+	dl   delims
+}
+
+// conditions returns the conditions of the node and of its element. The
+// condition of the node belongs to the sequence, it uses the default
+// delimiters instead of the delimiters of the element.
+func conditions(seq *Seq, decl *D, declElem *T) []condition {
+	return []condition{
+		{seq.Cond, defaultDelims},
+		{declElem.If, templateDelims(decl, declElem)},
+	}
+}
+
+// skipped reports whether the condition of the node or of its element is
+// false.
+func (g *Gojen) skipped(seq *Seq, decl *D, declElem *T, args Args) (bool, error) {
+	for _, c := range conditions(seq, decl, declElem) {
+		ok, err := g.evalCond(args, c.cond, c.dl)
+		if err != nil {
+			// the position is in the condition with its delimiters.
+			return false, newTemplateError(decl, declElem, "condition", condTemplate(c.cond, c.dl), err)
+		}
+		if !ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// build resolves the args of the node and builds its state, or one state per
+// item if the node loops over a list arg. It returns the resolved args. The
+// node is restored instead if its states are resumed.
+func (g *Gojen) build(seq *Seq, node string, i *int) (Args, error) {
 	decl := g.s.GetDecl(seq.DName)
 	if decl == nil {
-		return fmt.Errorf("Declaration '%s' not found", seq.DName)
+		return nil, &ErrDeclNotFound{DName: seq.DName}
 	}
 	declElem := decl.GetElements(seq.EName)
 	if declElem == nil {
-		return fmt.Errorf("Element '%s' not found in declaration '%s'", seq.EName, seq.DName)
+		return nil, &ErrElementNotFound{DName: seq.DName, EName: seq.EName}
+	}
+	// the template files of a declaration which is not loaded by LoadDecls
+	// are resolved from the working directory.
+	if err := decl.LoadTemplateFiles(""); err != nil {
+		return nil, fmt.Errorf("declaration '%s': %w", decl.Name, err)
+	}
+
+	if args, restored, err := g.restoreNode(seq, decl, declElem, node, i); err != nil || restored {
+		return args, err
 	}
 
 	var (
-		rawPath          = util.IfValue("", declElem.Path, decl.Path)
 		storeArgs, _     = g.s.GetArgs()
-		args             = NewArgs(storeArgs, decl.Args, declElem.Args, seq.tempArgs)
+		args             = NewArgs(storeArgs, decl.Args, declElem.Args, seq.TempArgs)
+		params           = decl.Params.Merge(declElem.Params)
 		requiredArgNames = util.NewSlice(decl.Require, declElem.Require, seq.ForwardArgs.Keys())
 	)
-
-	if _, notFoundArgNames := args.Extract(requiredArgNames...); len(notFoundArgNames) > 0 {
-		g.c.Dangerf(true, "Please provide the missing arguments [%s] in JSON format: ", strings.Join(notFoundArgNames, ", "))
-		jsonArgs, err := g.c.Scanln()
+	if seq.LoopArg == "" {
+		// the condition of a loop is evaluated for each item.
+		skipped, err := g.skipped(seq, decl, declElem, args.Clone().Merge(params.Defaults(args)))
 		if err != nil {
-			return err
+			return nil, err
 		}
-		parsedJSONArgs := NewArgs()
-		if err = json.Unmarshal(jsonArgs, &parsedJSONArgs); err != nil {
-			return err
+		if skipped {
+			g.c.Infof(!g.cfg.silent, "Skipped state: %s.%s\n", decl.Name, declElem.Name)
+			return args, nil
 		}
-		for _, k := range notFoundArgNames {
-			v, ok := parsedJSONArgs[k]
-			if !ok {
-				return fmt.Errorf("Required argument '%s' not found in the JSON", k)
-			}
-			args[k] = v
+	} else {
+		// the item arg is bound by the loop, the list arg is required instead.
+		requiredArgNames = slices.DeleteFunc(requiredArgNames, func(k string) bool { return k == seq.LoopAs })
+		requiredArgNames = append(requiredArgNames, seq.LoopArg)
+	}
+
+	if _, notFoundArgNames := args.Extract(requiredArgNames...); len(notFoundArgNames) > 0 {
+		answeredArgs, err := g.askArgs(seq, node, notFoundArgNames, params)
+		if err != nil {
+			return nil, err
 		}
+		args.Merge(answeredArgs)
+	}
+	args.Merge(params.Defaults(args))
+
+	if err := params.Coerce(args); err != nil {
+		return nil, &ErrInvalidArg{DName: decl.Name, EName: declElem.Name, Err: err}
 	}
 
 	forwardArgs := make(Args)
@@ -158,27 +341,82 @@ func (g *Gojen) build(seq *Seq, i *int) error {
 	}
 	g.s.UpdateArgs(forwardArgs)
 
-	parsedPath, err := g.parseTemplate(args, "path", rawPath)
+	if seq.LoopArg == "" {
+		return args, g.buildState(seq, node, decl, declElem, args, forwardArgs, i)
+	}
+
+	items, err := toList(args[seq.LoopArg])
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("invalid loop argument '%s' of '%s.%s': %w", seq.LoopArg, decl.Name, declElem.Name, err)
+	}
+	for _, item := range items {
+		itemArgs := args.Clone()
+		itemArgs[seq.LoopAs] = item
+		if err := params.Coerce(itemArgs); err != nil {
+			return nil, &ErrInvalidArg{DName: decl.Name, EName: declElem.Name, Err: err}
+		}
+
+		skipped, err := g.skipped(seq, decl, declElem, itemArgs)
+		if err != nil {
+			return nil, err
+		}
+		if skipped {
+			g.c.Infof(!g.cfg.silent, "Skipped state: %s.%s\n", decl.Name, declElem.Name)
+			continue
+		}
+
+		if err := g.buildState(seq, node, decl, declElem, itemArgs, forwardArgs, i); err != nil {
+			return nil, err
+		}
 	}
 
-	parsedTmpl, err := g.parseTemplate(args, "content", declElem.Template)
+	return args, nil
+}
+
+// buildState parses the templates of the element with the args, then adds the
+// state to the store and writes it into the local state directory.
+func (g *Gojen) buildState(seq *Seq, node string, decl *D, declElem *T, args, forwardArgs Args, i *int) error {
+	restored, err := g.restoreState(seq, decl, declElem, *i)
 	if err != nil {
 		return err
 	}
+	if restored {
+		*i++
+		return nil
+	}
+
+	var (
+		dl      = templateDelims(decl, declElem)
+		rawPath = util.IfValue("", declElem.Path, decl.Path)
+	)
+	parsedPath, err := g.parseTemplate(args, "path", rawPath, dl)
+	if err != nil {
+		return newTemplateError(decl, declElem, "path", rawPath, err)
+	}
+
+	parsedTmpl, err := g.parseTemplate(args, "content", declElem.Template, dl)
+	if err != nil {
+		return newTemplateError(decl, declElem, "content", declElem.Template, err)
+	}
 
-	stateOutput := make(map[string]*Output, len(declElem.Output))
+	var (
+		stateOutput = make(map[string]*Output, len(declElem.Output))
+		rawTmpls    = []string{rawPath, declElem.Template}
+	)
 	for k, v := range declElem.Output {
 		outputRawPath := util.IfValue("", v.Path, declElem.Path, decl.Path)
-		parsedOutputPath, err := g.parseTemplate(args, "path", outputRawPath)
+		rawTmpls = append(rawTmpls, outputRawPath, v.Template)
+		parsedOutputPath, err := g.parseTemplate(args, "path", outputRawPath, dl)
 		if err != nil {
-			return err
+			return newTemplateError(decl, declElem, fmt.Sprintf("path of output '%s'", k), outputRawPath, err)
 		}
 
-		parsedOutputTmpl, err := g.parseTemplate(args, "content", v.Template)
+		parsedOutputTmpl, err := g.parseTemplate(args, "content", v.Template, dl)
 		if err != nil {
-			return err
+			// the content of the output may be loaded from its own file.
+			tErr := newTemplateError(decl, declElem, fmt.Sprintf("content of output '%s'", k), v.Template, err)
+			tErr.Source = util.IfValue(tErr.Source, v.source)
+			return tErr
 		}
 		stateOutput[k] = &Output{
 			Path:     parsedOutputPath,
@@ -187,15 +425,30 @@ func (g *Gojen) build(seq *Seq, i *int) error {
 	}
 
 	rawAlias := util.IfValue(declElem.Name, declElem.Alias)
-	parsedAlias, err := g.parseTemplate(args, rawAlias, rawAlias)
+	parsedAlias, err := g.parseTemplate(args, "alias", rawAlias, dl)
 	if err != nil {
-		return err
+		return newTemplateError(decl, declElem, "alias", rawAlias, err)
+	}
+	rawTmpls = append(rawTmpls, rawAlias)
+
+	var missingArgs []string
+	if g.cfg.missingKey == MissingKeyWarn {
+		missing := util.MapExisting[string]{}
+		for _, tmpl := range rawTmpls {
+			missing.Add(g.missingArgs(args, tmpl, dl)...)
+		}
+		if len(missing) > 0 {
+			missingArgs = missing.Keys()
+			sort.Strings(missingArgs)
+			g.c.Warnf(!g.cfg.silent, "Missing args [%s] are rendered as zero values in '%s.%s'\n", strings.Join(missingArgs, ", "), decl.Name, declElem.Name)
+		}
 	}
 
 	st := &State{
 		seq:           seq,
 		d:             decl,
 		e:             declElem,
+		Node:          node,
 		Strategy:      declElem.Strategy,
 		DName:         decl.Name,
 		EName:         declElem.Name,
@@ -208,6 +461,7 @@ func (g *Gojen) build(seq *Seq, i *int) error {
 		ParsedPath:    parsedPath,
 		ForwardedArgs: forwardArgs,
 		Output:        stateOutput,
+		MissingArgs:   missingArgs,
 	}
 	g.s.AddState(st)
 
@@ -227,12 +481,34 @@ func (g *Gojen) build(seq *Seq, i *int) error {
 	return nil
 }
 
-// Build builds the templates.
+// Build builds the templates. In keep-going mode, a failing node does not stop
+// the build, the build continues with the nodes after it and returns an
+// ErrBuild with the errors of all failing nodes.
 func (g *Gojen) Build(seq *Seq) (err error) {
+	// branches are the cases which are being built, the outer first. The
+	// nodes which are reached by several cases are built after all of them.
+	type branches struct {
+		shared   map[*Seq]bool
+		deferred []*Seq
+	}
+
 	var (
 		travelSeq func(n *Seq) error
+		nodeIDs   = seq.nodeIDs()
 		flow      = []string{}
 		bIndex    = 0
+		visited   = map[*Seq]bool{}
+		stack     = []*branches{}
+		buildErr  = &ErrBuild{}
+		// fail returns the error of the node, or collects it in keep-going
+		// mode.
+		fail = func(n *Seq, err error) error {
+			if !g.cfg.keepGoing {
+				return err
+			}
+			buildErr.Errs = append(buildErr.Errs, &ErrNode{DName: n.DName, EName: n.EName, Err: err})
+			return nil
+		}
 	)
 
 	defer func() {
@@ -241,16 +517,45 @@ func (g *Gojen) Build(seq *Seq) (err error) {
 		}
 	}()
 
+	if _, err := ParseMissingKey(string(g.cfg.missingKey)); err != nil {
+		return err
+	}
+
+	if err := g.recordSeq(seq); err != nil {
+		return err
+	}
+
 	travelSeq = func(n *Seq) error {
 		if n.DName == "" && n.EName == "" {
 			return errors.New("invalid case selected")
 		}
 
-		if err := g.build(n, &bIndex); err != nil {
-			return err
+		// the nodes appended after a multi-select are shared by all selected
+		// cases, they are built once after the cases.
+		if visited[n] {
+			return nil
 		}
+		for _, b := range stack {
+			if b.shared[n] {
+				if !slices.Contains(b.deferred, n) {
+					b.deferred = append(b.deferred, n)
+				}
+				return nil
+			}
+		}
+		visited[n] = true
 
-		flow = append(flow, fmt.Sprintf("%s.%s", n.DName, n.EName))
+		args, err := g.build(n, nodeIDs[n], &bIndex)
+		if err != nil {
+			if err := fail(n, err); err != nil {
+				return err
+			}
+		} else {
+			if err := g.recordNode(nodeIDs[n]); err != nil {
+				return err
+			}
+			flow = append(flow, fmt.Sprintf("%s.%s", n.DName, n.EName))
+		}
 
 		if len(n.Cases) == 0 {
 			if n.Next != nil {
@@ -260,39 +565,25 @@ func (g *Gojen) Build(seq *Seq) (err error) {
 			return nil
 		}
 
-		g.c.Dangerf(true, "Which case do you want to choose?\n")
-		i := 1
-		mapIndexCases := map[int]*Seq{}
-		util.LoopStrMap(n.Cases, func(k string, c *Seq) {
-			g.c.Infof(true, "%d) %s.%s\n", i, c.DName, c.EName)
-			mapIndexCases[i] = c
-			i++
-		})
-
-		g.c.Dangerf(true, "Select case: ")
-		selectedBytes, err := g.c.Scanln()
+		cases, err := g.askCase(n, nodeIDs[n], args)
 		if err != nil {
-			return err
+			// the cases are not reachable.
+			return fail(n, err)
 		}
 
-		selected, err := strconv.Atoi(string(selectedBytes))
-		if err != nil {
-			return err
-		}
-
-		c, ok := mapIndexCases[selected]
-		if !ok {
-			return fmt.Errorf("invalid case selected")
-		}
-
-		if err := g.build(c, &bIndex); err != nil {
-			return err
+		b := &branches{shared: n.sharedNodes()}
+		stack = append(stack, b)
+		for _, c := range cases {
+			if err := travelSeq(c); err != nil {
+				return err
+			}
 		}
+		stack = stack[:len(stack)-1]
 
-		flow = append(flow, fmt.Sprintf("%s.%s", c.DName, c.EName))
-
-		if c.Next != nil {
-			return travelSeq(c.Next)
+		for _, d := range b.deferred {
+			if err := travelSeq(d); err != nil {
+				return err
+			}
 		}
 
 		return nil
@@ -300,33 +591,65 @@ func (g *Gojen) Build(seq *Seq) (err error) {
 	if err := travelSeq(seq.root); err != nil {
 		return err
 	}
+	if len(buildErr.Errs) > 0 {
+		return buildErr
+	}
 
 	g.c.Successf(!g.cfg.silent, "built sequences: %s\n", strings.Join(flow, " -> "))
 
 	return nil
 }
 
-func (g *Gojen) applyState(s *State) (err error) {
-	defer func() {
-		if err != nil {
-			return
+// applyOutputs appends the outputs of the state after their input anchors.
+func (g *Gojen) applyOutputs(f FileManager, s *State) error {
+	for outputName, output := range s.Output {
+		inputIndent := fmt.Sprintf("%s +gojen:input=%s->%s", g.cfg.commentQuote, s.EName, outputName)
+		if err := f.AppendContentAfter(output.Path, inputIndent, output.Template); err != nil {
+			return err
 		}
+	}
 
-		g.ModifiedFiles.Add(s.ParsedPath)
-	}()
+	return nil
+}
+
+// confirmDuplicated asks user to continue if the parsed content of the state
+// is already in the file. Nothing is asked in dry run.
+func (g *Gojen) confirmDuplicated(f FileManager, s *State, dryRun bool) (bool, error) {
+	if dryRun {
+		return true, nil
+	}
+
+	percent, highlighted, err := f.CompareContentWithFile(s.ParsedTmpl, s.ParsedPath, util.MapExisting[string]{})
+	if err != nil {
+		return false, err
+	}
+
+	if percent > 0 {
+		g.c.Dangerf(true, "Detected percent of same content %f of '%s':\n", percent, s.ParsedPath)
+		g.c.Printf(true, "%s\n", highlighted)
+		return g.confirm(QuestionDuplicatedContent, s.ParsedPath, "Do you still want to continue (y/N)? ")
+	}
 
+	return true, nil
+}
+
+// applyState applies the state using the given file manager. In dry run, the
+// changes are only recorded by the file manager, nothing is asked or reported
+// as written.
+func (g *Gojen) applyState(f FileManager, s *State, dryRun bool) error {
 	switch s.Strategy {
 	case StrategyInit:
-		created, err := g.f.CreateFileIfNotExist(s.ParsedPath, s.ParsedTmpl)
+		created, err := f.CreateFileIfNotExist(s.ParsedPath, s.ParsedTmpl)
 		if err != nil {
 			return err
 		}
 
-		for outputName, output := range s.Output {
-			inputIndent := fmt.Sprintf("%s +gojen:input=%s->%s", g.cfg.commentQuote, s.EName, outputName)
-			if err := g.f.AppendContentAfter(output.Path, inputIndent, output.Template); err != nil {
-				return err
-			}
+		if err := g.applyOutputs(f, s); err != nil {
+			return err
+		}
+
+		if dryRun {
+			return nil
 		}
 
 		if created {
@@ -337,84 +660,134 @@ func (g *Gojen) applyState(s *State) (err error) {
 
 		g.c.Infof(!g.cfg.silent, "File already exists: '%s'. Skipped to init the file\n", s.ParsedPath)
 		return nil
-	case StrategyAppend:
-		exist := g.f.FileExists(s.ParsedPath)
+	case StrategyPrepend, StrategyAppend:
+		exist := f.FileExists(s.ParsedPath)
 		if !exist {
-			g.c.Infof(!g.cfg.silent, "File %s does not exist. Skipped to append parsed content\n", s.ParsedPath)
+			g.c.Infof(!g.cfg.silent, "File %s does not exist. Skipped to %s parsed content\n", s.ParsedPath, s.Strategy)
 			return nil
 		}
 
-		percent, highlighted, err := g.f.CompareContentWithFile(s.ParsedTmpl, s.ParsedPath, util.MapExisting[string]{})
-		if err != nil {
+		if ok, err := g.confirmDuplicated(f, s, dryRun); err != nil || !ok {
 			return err
 		}
 
-		if percent > 0 {
-			g.c.Dangerf(true, "Detected percent of same content %f of '%s':\n", percent, s.ParsedPath)
-			g.c.Printf(true, "%s\n", highlighted)
-			if ok := g.c.PerformYesNo("Do you still want to continue (y/N)? "); !ok {
-				return nil
-			}
+		if err := g.applyOutputs(f, s); err != nil {
+			return err
 		}
 
-		lineIndent := fmt.Sprintf("%s +gojen:append=%s", g.cfg.commentQuote, s.ParsedEAlias)
-		for outputName, output := range s.Output {
-			inputIndent := fmt.Sprintf("%s +gojen:input=%s->%s", g.cfg.commentQuote, s.EName, outputName)
-			if err := g.f.AppendContentAfter(output.Path, inputIndent, output.Template); err != nil {
-				return err
-			}
+		lineIndent := fmt.Sprintf("%s +gojen:%s=%s", g.cfg.commentQuote, s.Strategy, s.ParsedEAlias)
+		if s.Strategy == StrategyPrepend {
+			return f.PrependContentBefore(s.ParsedPath, lineIndent, s.ParsedTmpl)
 		}
-		return g.f.AppendContentAfter(s.ParsedPath, lineIndent, s.ParsedTmpl)
-	case StrategyAppendAtPos:
-		exist := g.f.FileExists(s.ParsedPath)
+		return f.AppendContentAfter(s.ParsedPath, lineIndent, s.ParsedTmpl)
+	case StrategyPrependAtHead, StrategyAppendAtPos:
+		exist := f.FileExists(s.ParsedPath)
 		if !exist {
-			if ok := g.c.PerformYesNo("File %s does not exist. Do you want to create it to append parsed content?", s.ParsedPath); !ok {
-				g.c.Infof(!g.cfg.silent, "User skipped to create file: %s\n", s.ParsedPath)
-				return nil
+			// the plan shows the file as created.
+			if !dryRun {
+				ok, err := g.confirm(QuestionCreateFile, s.ParsedPath, "File %s does not exist. Do you want to create it to %s parsed content?", s.ParsedPath, s.Strategy)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					g.c.Infof(!g.cfg.silent, "User skipped to create file: %s\n", s.ParsedPath)
+					return nil
+				}
 			}
 
-			return g.f.TruncWithContent(s.ParsedPath, s.ParsedTmpl)
+			return f.TruncWithContent(s.ParsedPath, s.ParsedTmpl)
 		}
 
-		percent, highlighted, err := g.f.CompareContentWithFile(s.ParsedTmpl, s.ParsedPath, util.MapExisting[string]{})
-		if err != nil {
+		if ok, err := g.confirmDuplicated(f, s, dryRun); err != nil || !ok {
 			return err
 		}
 
-		if percent > 0 {
-			g.c.Dangerf(true, "Detected percent of same content %f of '%s':\n", percent, s.ParsedPath)
-			g.c.Printf(true, "%s\n", highlighted)
-			if ok := g.c.PerformYesNo("Do you still want to continue (y/N)? "); !ok {
-				return nil
-			}
+		if err := g.applyOutputs(f, s); err != nil {
+			return err
 		}
 
-		for outputName, output := range s.Output {
-			inputIndent := fmt.Sprintf("%s +gojen:input=%s->%s", g.cfg.commentQuote, s.EName, outputName)
-			if err := g.f.AppendContentAfter(output.Path, inputIndent, output.Template); err != nil {
-				return err
-			}
+		if s.Strategy == StrategyPrependAtHead {
+			return f.PrependContent(s.ParsedPath, s.ParsedTmpl)
+		}
+		return f.AppendContent(s.ParsedPath, s.ParsedTmpl)
+	case StrategyEdit:
+		exist := f.FileExists(s.ParsedPath)
+		if !exist {
+			g.c.Infof(!g.cfg.silent, "File %s does not exist. Skipped to edit parsed content\n", s.ParsedPath)
+			return nil
+		}
+
+		if err := g.applyOutputs(f, s); err != nil {
+			return err
 		}
-		return g.f.AppendContent(s.ParsedPath, s.ParsedTmpl)
+
+		var (
+			startIndent = fmt.Sprintf("%s +gojen:edit=%s", g.cfg.commentQuote, s.ParsedEAlias)
+			endIndent   = fmt.Sprintf("%s +gojen:end=%s", g.cfg.commentQuote, s.ParsedEAlias)
+		)
+		return f.ReplaceContentAt(s.ParsedPath, startIndent, endIndent, s.ParsedTmpl)
 	default:
-		fmt.Println("TODO: implement other strategies")
+		return &ErrUnknownStrategy{DName: s.DName, EName: s.EName, Strategy: s.Strategy}
 	}
+}
 
-	return nil
+// stage returns a file manager which keeps all writes in memory.
+func (g *Gojen) stage() StagedFileManager {
+	return filemanager.NewStagedWithConfig(g.cfg.fileManager)
+}
+
+// Plan applies the built states to an in-memory copy of the target files and
+// returns the planned changes. Nothing is written to disk and the built states
+// are kept to be applied later.
+func (g *Gojen) Plan() (*Plan, error) {
+	if g.Err != nil {
+		return nil, g.Err
+	}
+
+	f := g.stage()
+	for _, bs := range g.s.GetStates() {
+		if err := g.applyState(f, bs, true); err != nil {
+			return nil, err
+		}
+	}
+
+	return newPlan(f.Changes()), nil
 }
 
-// Gojen applies the built templates.
+// Gojen applies the built templates. All writes are staged in memory and
+// committed at the end, so either all states are applied or none of them.
 func (g *Gojen) Apply() error {
 	if g.Err != nil {
 		return g.Err
 	}
 
-	for _, bs := range g.s.GetStates() {
-		if err := g.applyState(bs); err != nil {
+	f := g.stage()
+	states := g.s.GetStates()
+	for _, bs := range states {
+		if err := g.applyState(f, bs, false); err != nil {
 			return err
 		}
 	}
 
+	bk, err := g.backup(f.Changes())
+	if err != nil {
+		return err
+	}
+
+	// the manifest is saved only if the changes are committed, a rolled back
+	// apply has nothing to undo.
+	if err := f.Commit(); err != nil {
+		return err
+	}
+
+	if err := g.saveBackup(bk); err != nil {
+		return err
+	}
+
+	for _, bs := range states {
+		g.ModifiedFiles.Add(bs.ParsedPath)
+	}
+
 	g.s.Clean()
 
 	return nil
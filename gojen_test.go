@@ -1,9 +1,18 @@
 package gojen
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/cirius-go/gojen/lib/cli"
+	"github.com/cirius-go/gojen/lib/filemanager"
+	"github.com/cirius-go/gojen/util/testlib"
 )
 
 // TestNew test new gojen with default configuration.
@@ -12,3 +21,1100 @@ func TestNew(t *testing.T) {
 	c := C()
 	assert.Equal(t, g.cfg, c, "config should be equal")
 }
+
+// TestApplyStrategies tests applying the anchor based strategies.
+func TestApplyStrategies(t *testing.T) {
+	dir := testlib.CreateDir(t)
+	var (
+		path   = filepath.Join(dir, "main.go")
+		routes = filepath.Join(dir, "routes.go")
+	)
+	testlib.NewFileWithContent(t, routes, `package main
+// +gojen:input=header->log
+// +gojen:input=imports->log
+// +gojen:input=body->log
+`)
+	testlib.NewFileWithContent(t, path, `package main
+// +gojen:prepend=imports
+func main() {
+	// +gojen:edit=body
+	println("old")
+	// +gojen:end=body
+}
+`)
+
+	g := NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()))
+	g.SetDecls(&D{
+		Name: "main",
+		Path: path,
+		Templates: []*T{
+			{Name: "header", Strategy: StrategyPrependAtHead, Template: "// Code generated by gojen.\n", Output: map[string]*Output{"log": {Path: routes, Template: "// header"}}},
+			{Name: "imports", Strategy: StrategyPrepend, Template: `import "fmt"`, Output: map[string]*Output{"log": {Path: routes, Template: "// imports"}}},
+			{Name: "body", Strategy: StrategyEdit, Template: `	fmt.Println("new")`, Output: map[string]*Output{"log": {Path: routes, Template: "// body"}}},
+		},
+	})
+
+	err := g.Build(NewSeq("main", "header", "imports", "body"))
+	assert.Nil(t, err)
+	assert.Nil(t, g.Apply())
+
+	b, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, `// Code generated by gojen.
+package main
+import "fmt"
+// +gojen:prepend=imports
+func main() {
+	// +gojen:edit=body
+	fmt.Println("new")
+	// +gojen:end=body
+}
+`, string(b))
+
+	// the outputs are appended after their input anchors.
+	b, err = os.ReadFile(routes)
+	assert.Nil(t, err)
+	assert.Equal(t, `package main
+// +gojen:input=header->log
+// header
+// +gojen:input=imports->log
+// imports
+// +gojen:input=body->log
+// body
+`, string(b))
+}
+
+// TestPlan tests planning the built states without touching the disk.
+func TestPlan(t *testing.T) {
+	dir := testlib.CreateDir(t)
+	path := filepath.Join(dir, "svc.go")
+
+	g := NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()))
+	g.SetDecls(&D{
+		Name: "svc",
+		Path: path,
+		Templates: []*T{
+			{Name: "init", Strategy: StrategyInit, Template: "package svc\n// +gojen:append=method\n"},
+			{Name: "method", Strategy: StrategyAppend, Template: "func {{ .Method }}() {}"},
+		},
+	})
+	g.UpdateArgs(Args{"Method": "Get"})
+
+	err := g.Build(NewSeq("svc", "init", "method"))
+	assert.Nil(t, err)
+
+	p, err := g.Plan()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{path}, p.Created())
+	assert.Empty(t, p.Modified())
+	assert.Equal(t, "--- /dev/null\n+++ b/"+strings.TrimPrefix(filepath.ToSlash(path), "/")+"\n@@ -0,0 +1,3 @@\n+package svc\n+// +gojen:append=method\n+func Get() {}\n", p.Files[0].Diff)
+	assert.NoFileExists(t, path)
+}
+
+// TestPlanDryRun tests that the plan does not ask to create a file or to
+// continue with duplicated content.
+func TestPlanDryRun(t *testing.T) {
+	dir := testlib.CreateDir(t)
+	var (
+		created    = filepath.Join(dir, "new.go")
+		duplicated = filepath.Join(dir, "dup.go")
+	)
+	testlib.NewFileWithContent(t, duplicated, "package dup\nfunc Get() {}\n")
+
+	g := NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()).SetInteractive(false))
+	g.SetDecls(&D{
+		Name: "svc",
+		Templates: []*T{
+			{Name: "new", Path: created, Strategy: StrategyAppendAtPos, Template: "package svc\n"},
+			{Name: "dup", Path: duplicated, Strategy: StrategyAppendAtPos, Template: "func Get() {}\n"},
+		},
+	})
+
+	err := g.Build(NewSeq("svc", "new", "dup"))
+	assert.Nil(t, err)
+
+	p, err := g.Plan()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{created}, p.Created())
+	assert.Equal(t, []string{duplicated}, p.Modified())
+	assert.NoFileExists(t, created)
+
+	// applying still asks the questions.
+	assert.ErrorContains(t, g.Apply(), "is not answered in non-interactive mode")
+}
+
+// TestApplyAllOrNothing tests that no file is written if any state fails.
+func TestApplyAllOrNothing(t *testing.T) {
+	dir := testlib.CreateDir(t)
+	path := filepath.Join(dir, "main.go")
+
+	g := NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()))
+	g.SetDecls(&D{
+		Name: "main",
+		Path: path,
+		Templates: []*T{
+			{Name: "init", Strategy: StrategyInit, Template: "package main\n// +gojen:edit=body\n"},
+			{Name: "body", Strategy: StrategyEdit, Template: "func main() {}"},
+		},
+	})
+
+	err := g.Build(NewSeq("main", "init", "body"))
+	assert.Nil(t, err)
+	assert.NotNil(t, g.Apply())
+	assert.NoFileExists(t, path)
+}
+
+// TestCommitRollback tests restoring the committed files if a later file
+// fails to be committed.
+func TestCommitRollback(t *testing.T) {
+	dir := testlib.CreateDir(t)
+	var (
+		created  = filepath.Join(dir, "a.go")
+		modified = filepath.Join(dir, "m.go")
+		failed   = filepath.Join(dir, "x", "b.go")
+	)
+	testlib.NewFileWithContent(t, modified, "package m\n")
+
+	f := filemanager.NewStaged()
+	assert.Nil(t, f.TruncWithContent(created, "package a\n"))
+	assert.Nil(t, f.AppendContent(modified, "// modified\n"))
+	assert.Nil(t, f.TruncWithContent(failed, "package x\n"))
+
+	// x becomes a file, so x/b.go can not be committed.
+	testlib.NewFileWithContent(t, filepath.Join(dir, "x"), "")
+
+	assert.NotNil(t, f.Commit())
+	assert.NoFileExists(t, created)
+	b, err := os.ReadFile(modified)
+	assert.Nil(t, err)
+	assert.Equal(t, "package m\n", string(b))
+}
+
+// TestUndo tests reverting the files touched by an applied build.
+func TestUndo(t *testing.T) {
+	dir := testlib.CreateDir(t)
+	var (
+		created  = filepath.Join(dir, "svc.go")
+		modified = filepath.Join(dir, "main.go")
+	)
+	testlib.NewFileWithContent(t, modified, "package main\n")
+
+	g := NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()))
+	g.SetDecls(&D{
+		Name: "main",
+		Templates: []*T{
+			{Name: "init", Path: created, Strategy: StrategyInit, Template: "package svc\n"},
+			{Name: "end", Path: modified, Strategy: StrategyAppendAtPos, Template: "// end\n"},
+		},
+	})
+
+	err := g.Build(NewSeq("main", "init", "end"))
+	assert.Nil(t, err)
+	assert.Nil(t, g.Apply())
+	assert.FileExists(t, created)
+
+	assert.Nil(t, g.Undo(g.BuildID()))
+	assert.NoFileExists(t, created)
+	b, err := os.ReadFile(modified)
+	assert.Nil(t, err)
+	assert.Equal(t, "package main\n", string(b))
+
+	assert.NotNil(t, g.Undo(g.BuildID()), "build should have nothing to undo")
+}
+
+// TestUndoFailedApply tests that an apply which is rolled back has nothing to
+// undo.
+func TestUndoFailedApply(t *testing.T) {
+	dir := testlib.CreateDir(t)
+	var (
+		created = filepath.Join(dir, "a.go")
+		failed  = filepath.Join(dir, "x", "b.go")
+	)
+
+	g := NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()))
+	g.SetDecls(&D{
+		Name: "main",
+		Templates: []*T{
+			{Name: "a", Path: created, Strategy: StrategyInit, Template: "package a\n"},
+			{Name: "b", Path: failed, Strategy: StrategyInit, Template: "package x\n"},
+		},
+	})
+
+	err := g.Build(NewSeq("main", "a", "b"))
+	assert.Nil(t, err)
+
+	// x is a dangling link, so x/b.go is staged but can not be committed.
+	assert.Nil(t, os.Symlink(filepath.Join(dir, "missing"), filepath.Join(dir, "x")))
+
+	assert.ErrorContains(t, g.Apply(), "error committing")
+	assert.NoFileExists(t, created)
+
+	_, err = g.LastAppliedBuildID()
+	assert.NotNil(t, err)
+	assert.ErrorContains(t, g.Undo(g.BuildID()), "has nothing to undo")
+}
+
+// TestNonInteractive tests answering the questions from the answers.
+func TestNonInteractive(t *testing.T) {
+	dir := testlib.CreateDir(t)
+	path := filepath.Join(dir, "api.go")
+
+	newGojen := func(a *Answers) *Gojen {
+		g := NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()).SetInteractive(false).SetAnswers(a))
+		g.SetDecls(&D{
+			Name:    "api",
+			Path:    path,
+			Require: []string{"Domain"},
+			Templates: []*T{
+				{Name: "init", Strategy: StrategyInit, Template: "package {{ .Domain }}\n"},
+				{Name: "get", Strategy: StrategyAppendAtPos, Template: "func Get() {}\n"},
+				{Name: "post", Strategy: StrategyAppendAtPos, Template: "func Post() {}\n"},
+			},
+		})
+		return g
+	}
+	seq := NewSeq("api", "init").Select("api", []string{"get", "post"}, nil)
+
+	err := newGojen(nil).Build(seq)
+	assert.ErrorContains(t, err, "missing required arguments [Domain] of 'api.init'")
+
+	a := NewAnswers()
+	a.Args["Domain"] = "user"
+	err = newGojen(a).Build(seq)
+	assert.ErrorContains(t, err, "case of 'api.init' is not answered")
+
+	a.Cases["api.init"] = "post"
+	g := newGojen(a)
+	assert.Nil(t, g.Build(seq))
+	assert.Nil(t, g.Apply())
+
+	b, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "package user\nfunc Post() {}\n", string(b))
+}
+
+// TestReplay tests replaying a recorded interactive build.
+func TestReplay(t *testing.T) {
+	var (
+		dir       = testlib.CreateDir(t)
+		storePath = t.TempDir()
+		decl      = &D{
+			Name: "api",
+			Path: filepath.Join(dir, "api.go"),
+			Templates: []*T{
+				{Name: "init", Require: []string{"Domain"}, Strategy: StrategyInit, Template: "package {{ .Domain }}\n"},
+				{Name: "get", Strategy: StrategyAppendAtPos, Template: "func Get() {}\n"},
+				{Name: "post", Strategy: StrategyAppendAtPos, Template: "func Post() {}\n"},
+			},
+		}
+	)
+
+	c := cli.NewConsole()
+	c.SetOutput(io.Discard)
+	c.SetInput(strings.NewReader("user\n"))
+
+	g := NewWithConfig(C().SetSilent(true).SetStorePath(storePath).SetAnswers(&Answers{Cases: map[string]string{"api.init": "post"}}))
+	g.c = c
+	g.SetDecls(decl)
+	assert.Nil(t, g.Build(NewSeq("api", "init").Select("api", []string{"get", "post"}, nil)))
+
+	rec, err := LoadRecording(storePath, g.BuildID())
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]*NodeAnswers{"n0": {Args: Args{"Domain": "user"}, Case: "post"}}, rec.Answers.Nodes)
+
+	replayed := NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()))
+	replayed.SetDecls(decl)
+	assert.Nil(t, replayed.Replay(rec))
+	assert.Len(t, replayed.States(), 2)
+	for i, s := range replayed.States() {
+		assert.Equal(t, g.States()[i].ParsedPath, s.ParsedPath)
+		assert.Equal(t, g.States()[i].ParsedTmpl, s.ParsedTmpl)
+	}
+
+	// the node appended after a multi-select is shared by the cases.
+	shared := &D{
+		Name: "d",
+		Path: filepath.Join(dir, "d.go"),
+		Templates: []*T{
+			{Name: "root", Strategy: StrategyAppendAtPos, Template: "root\n"},
+			{Name: "a", Strategy: StrategyAppendAtPos, Template: "a\n"},
+			{Name: "b", Strategy: StrategyAppendAtPos, Template: "b\n"},
+			{Name: "tail", Strategy: StrategyAppendAtPos, Template: "tail\n"},
+		},
+	}
+	storePath = t.TempDir()
+	g = NewWithConfig(C().SetSilent(true).SetStorePath(storePath).SetInteractive(false).SetAnswers(&Answers{Cases: map[string]string{"d.root": "a,b"}}))
+	g.SetDecls(shared)
+	assert.Nil(t, g.Build(NewSeq("d", "root").SelectMulti("d", []string{"a", "b"}, nil).Append("d", "tail")))
+	assert.Len(t, g.States(), 4)
+
+	rec, err = LoadRecording(storePath, g.BuildID())
+	assert.Nil(t, err)
+	assert.Same(t, rec.Seq.Cases["a"].Next, rec.Seq.Cases["b"].Next)
+
+	replayed = NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()))
+	replayed.SetDecls(shared)
+	assert.Nil(t, replayed.Replay(rec))
+	assert.Equal(t, builtENames(g), builtENames(replayed))
+}
+
+// TestParams tests the defaults and validation of the argument schemas.
+func TestParams(t *testing.T) {
+	newGojen := func(args Args) *Gojen {
+		g := NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()).SetInteractive(false))
+		g.SetDecls(&D{
+			Name: "api",
+			Path: "api.go",
+			Params: Params{
+				"Method": {Type: ParamTypeEnum, Values: []string{"get", "post"}, Default: "get"},
+				"Port":   {Type: ParamTypeInt},
+			},
+			Require: []string{"Method", "Port"},
+			Templates: []*T{
+				{
+					Name:     "handler",
+					Strategy: StrategyInit,
+					Params:   Params{"Domain": {Pattern: "^[a-z]+$"}},
+					Template: "{{ .Domain }} {{ .Method }} {{ .Port }}",
+				},
+			},
+		})
+		g.UpdateArgs(args)
+		return g
+	}
+	seq := NewSeq("api", "handler")
+
+	g := newGojen(Args{"Domain": "user", "Port": "8080"})
+	assert.Nil(t, g.Build(seq))
+	assert.Equal(t, 8080, g.States()[0].Args["Port"])
+	assert.Equal(t, "user get 8080", g.States()[0].ParsedTmpl)
+
+	err := newGojen(Args{"Domain": "user", "Port": "8080", "Method": "put"}).Build(seq)
+	assert.ErrorContains(t, err, "invalid arguments of 'api.handler': argument 'Method': 'put' is not one of [get, post]")
+
+	err = newGojen(Args{"Domain": "User", "Port": 8080}).Build(seq)
+	assert.ErrorContains(t, err, "argument 'Domain': 'User' does not match pattern '^[a-z]+$'")
+
+	err = (&D{Name: "d", Path: "p", Params: Params{"X": {Type: "float"}}, Templates: []*T{{Name: "e"}}}).Validate()
+	assert.ErrorContains(t, err, "param 'X': unknown type 'float'")
+
+	err = (&D{Name: "d", Path: "p", Params: Params{"X": {Pattern: "[a-"}}, Templates: []*T{{Name: "e"}}}).Validate()
+	assert.ErrorContains(t, err, "param 'X': invalid pattern")
+
+	// the declarations which are set directly are not validated.
+	g = newGojen(Args{"Domain": "user", "Port": 8080})
+	g.Decl("api").Params["Method"].Pattern = "[a-"
+	err = g.Build(seq)
+	assert.ErrorContains(t, err, "argument 'Method': invalid pattern")
+}
+
+// TestAskArgs tests asking the missing arguments one by one.
+func TestAskArgs(t *testing.T) {
+	c := cli.NewConsole()
+	c.SetOutput(io.Discard)
+	// invalid values are asked again.
+	c.SetInput(strings.NewReader("put\n2\n\nabc\n42\n"))
+
+	g := NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()))
+	g.c = c
+
+	args, err := g.askArgs(NewSeq("api", "handler"), "n0", []string{"Port", "Method"}, Params{
+		"Method": {Type: ParamTypeEnum, Values: []string{"get", "post"}},
+		"Port":   {Type: ParamTypeInt},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, Args{"Method": "post", "Port": 42}, args)
+
+	_, err = g.askArgs(NewSeq("api", "handler"), "n0", []string{"Domain"}, nil)
+	assert.ErrorContains(t, err, "error reading argument 'Domain'")
+}
+
+// TestSelectCases tests selecting cases by name, by default and several
+// cases at once.
+func TestSelectCases(t *testing.T) {
+	newGojen := func(input string, a *Answers) *Gojen {
+		g := newInteractiveGojen(t, input, &D{
+			Name: "api",
+			Path: "api.go",
+			Templates: []*T{
+				{Name: "init", Strategy: StrategyInit},
+				{Name: "get", Strategy: StrategyAppendAtPos},
+				{Name: "post", Strategy: StrategyAppendAtPos},
+				{Name: "delete", Strategy: StrategyAppendAtPos},
+				{Name: "register", Strategy: StrategyAppendAtPos},
+			},
+		})
+		g.cfg.SetAnswers(a)
+		return g
+	}
+	newSeq := func() *Seq {
+		return NewSeq("api", "init").
+			SelectMulti("api", []string{"get", "post", "delete"}, nil).
+			Default("get").
+			Append("api", "register")
+	}
+
+	g := newGojen("unknown\ndelete, 2\n", nil)
+	assert.Nil(t, g.Build(newSeq()))
+	assert.Equal(t, []string{"init", "delete", "get", "register"}, builtENames(g))
+
+	g = newGojen("", nil)
+	assert.Nil(t, g.Build(newSeq()))
+	assert.Equal(t, []string{"init", "get", "register"}, builtENames(g))
+
+	g = newGojen("", &Answers{Cases: map[string]string{"api.init": "post,delete"}})
+	assert.Nil(t, g.Build(newSeq()))
+	assert.Equal(t, []string{"init", "post", "delete", "register"}, builtENames(g))
+
+	// the nodes shared by the cases of a nested multi-select are built after
+	// them, and before the other cases of the outer multi-select.
+	g = newGojen("", &Answers{Cases: map[string]string{"api.init": "get,post", "api.get": "delete,register"}})
+	nested := NewSeq("api", "init").SelectMulti("api", []string{"get", "post"}, nil)
+	nested.Cases["get"].SelectMulti("api", []string{"delete", "register"}, nil).Append("api", "init")
+	assert.Nil(t, g.Build(nested))
+	assert.Equal(t, []string{"init", "get", "delete", "register", "init", "post"}, builtENames(g))
+
+	err := newGojen("", nil).Build(NewSeq("api", "init").Select("api", []string{"get", "post"}, nil).Default("get,post"))
+	assert.ErrorContains(t, err, "only one case can be selected in 'api.init'")
+}
+
+// TestSelectBy tests selecting cases by the value of an argument, and asking
+// the cases if the argument is not given.
+func TestSelectBy(t *testing.T) {
+	newGojen := func(input string, args Args) *Gojen {
+		g := newInteractiveGojen(t, input, &D{
+			Name: "svc",
+			Path: "svc.go",
+			Templates: []*T{
+				{Name: "init", Strategy: StrategyInit},
+				{Name: "crud", Strategy: StrategyAppendAtPos},
+				{Name: "readonly", Strategy: StrategyAppendAtPos},
+			},
+		})
+		g.UpdateArgs(args)
+		return g
+	}
+	newSeq := func() *Seq {
+		return NewSeq("svc", "init").SelectBy("Kind", "svc", []string{"crud", "readonly"}, nil)
+	}
+
+	g := newGojen("", Args{"Kind": "crud"})
+	assert.Nil(t, g.Build(newSeq()))
+	assert.Equal(t, []string{"init", "crud"}, builtENames(g))
+
+	multi := newSeq()
+	multi.MultiSelect = true
+	g = newGojen("", Args{"Kind": []any{"readonly", "crud"}})
+	assert.Nil(t, g.Build(multi))
+	assert.Equal(t, []string{"init", "readonly", "crud"}, builtENames(g))
+
+	g = newGojen("readonly\n", nil)
+	assert.Nil(t, g.Build(newSeq()))
+	assert.Equal(t, []string{"init", "readonly"}, builtENames(g))
+
+	err := newGojen("", Args{"Kind": "unknown"}).Build(newSeq())
+	assert.ErrorContains(t, err, "argument 'Kind': invalid case 'unknown' selected in 'svc.init'")
+}
+
+// TestLoop tests building a node once per item of a list argument.
+func TestLoop(t *testing.T) {
+	g := NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()).SetInteractive(false))
+	g.SetDecls(&D{
+		Name: "svc",
+		Path: "svc.go",
+		Templates: []*T{
+			{Name: "init", Strategy: StrategyInit, Template: "package svc"},
+			{Name: "createHandler", Require: []string{"Method"}, Strategy: StrategyAppendAtPos, Template: "func {{ .Method }}() {}"},
+		},
+	})
+	g.UpdateArgs(Args{"Methods": "get, list,create"})
+
+	assert.Nil(t, g.Build(NewSeq("svc", "init").AppendLoop("Methods", "Method", "svc", "createHandler")))
+
+	tmpls := []string{}
+	for _, s := range g.States() {
+		tmpls = append(tmpls, s.ParsedTmpl)
+	}
+	assert.Equal(t, []string{"package svc", "func get() {}", "func list() {}", "func create() {}"}, tmpls)
+
+	g.UpdateArgs(Args{"Methods": 1})
+	err := g.Build(NewSeq("svc", "init").AppendLoop("Methods", "Method", "svc", "createHandler"))
+	assert.ErrorContains(t, err, "invalid loop argument 'Methods' of 'svc.createHandler'")
+}
+
+// TestConditions tests skipping the nodes and the elements whose condition is
+// false.
+func TestConditions(t *testing.T) {
+	g := NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()).SetInteractive(false))
+	g.SetDecls(&D{
+		Name: "model",
+		Path: "model.go",
+		Templates: []*T{
+			{Name: "init", Strategy: StrategyInit},
+			{Name: "view", Require: []string{"View"}, Strategy: StrategyAppendAtPos, If: ".WithView"},
+			{Name: "action", Strategy: StrategyAppendAtPos},
+			{Name: "pagination", Strategy: StrategyAppendAtPos},
+		},
+	})
+	newSeq := func() *Seq {
+		return NewSeq("model", "init", "view").
+			Append("model", "action").If("{{ if .RBAC }}true{{ end }}").
+			AppendLoop("Methods", "Method", "model", "pagination").If(`eq .Method "list"`)
+	}
+
+	g.UpdateArgs(Args{"Methods": "get,list"})
+	assert.Nil(t, g.Build(newSeq()))
+	assert.Equal(t, []string{"init", "pagination:list"}, builtENames(g))
+
+	g.UpdateArgs(Args{"WithView": true, "View": "detail", "RBAC": true})
+	assert.Nil(t, g.Build(newSeq()))
+	assert.Equal(t, []string{"init", "pagination:list", "init", "view", "action", "pagination:list"}, builtENames(g))
+
+	err := g.Build(NewSeq("model", "init").If("{{ .Unclosed"))
+	assert.ErrorContains(t, err, "invalid condition of 'model.init'")
+}
+
+// TestValidate tests reporting all problems of every branch of a sequence
+// without building it.
+func TestValidate(t *testing.T) {
+	g := NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()).SetInteractive(false))
+	g.SetDecls(&D{
+		Name:    "svc",
+		Path:    "svc.go",
+		Require: []string{"Domain"},
+		Templates: []*T{
+			{Name: "init", Strategy: StrategyInit},
+			{Name: "handler", Require: []string{"Method"}, Strategy: StrategyAppendAtPos},
+			{Name: "broken", Strategy: "unknown"},
+			{Name: "view", Require: []string{"View"}, Strategy: StrategyAppendAtPos, If: ".WithView"},
+		},
+	})
+
+	valid := NewSeq("svc", "init").
+		Forward("Domain").
+		AppendWith(Args{"Method": "get"}, "svc", "handler").
+		AppendLoop("Methods", "Method", "svc", "handler").
+		Append("svc", "view")
+	g.UpdateArgs(Args{"Domain": "customer", "Methods": "list", "WithView": false})
+	assert.Nil(t, g.Validate(valid))
+
+	invalid := NewSeq("svc", "init").
+		Select("svc", []string{"handler", "broken", "missing"}, func(ss SeqSwitcher) {
+			ss.When("missing", func(c *Seq) *Seq {
+				return c.Append("unknown", "init")
+			})
+		}).
+		Default("other")
+	err := g.Validate(invalid)
+	assert.EqualError(t, err, `sequence 'svc.init' is invalid:
+  - invalid default case: invalid case 'other' selected in 'svc.init'
+  - unknown strategy 'unknown' of element 'svc.broken'
+  - missing required arguments [Method] of 'svc.handler'
+  - Element 'missing' not found in declaration 'svc'
+  - Declaration 'unknown' not found`)
+
+	// a skipped node forwards nothing.
+	skipped := NewSeq("svc", "view").Forward("Method").Append("svc", "handler")
+	assert.EqualError(t, g.Validate(skipped), "sequence 'svc.view' is invalid:\n  - missing required arguments [Method] of 'svc.handler'")
+
+	// a node is not skipped if its condition reads an answered, forwarded or
+	// unknown arg.
+	forwarded := NewSeq("svc", "init").Forward("WithView").Append("svc", "view")
+	g.cfg.SetAnswers(&Answers{Args: Args{"WithView": true}})
+	assert.EqualError(t, g.Validate(forwarded), "sequence 'svc.init' is invalid:\n  - missing required arguments [View] of 'svc.view'")
+	g.cfg.SetAnswers(nil)
+	assert.EqualError(t, g.Validate(NewSeq("svc", "view").If(".Unknown")), "sequence 'svc.view' is invalid:\n  - missing required arguments [View] of 'svc.view'")
+
+	// nothing is built.
+	assert.Empty(t, g.States())
+}
+
+// TestResume tests continuing a failed build from its saved states.
+func TestResume(t *testing.T) {
+	var (
+		storePath = t.TempDir()
+		newDecl   = func(initTmpl, postTmpl string) *D {
+			return &D{
+				Name: "api",
+				Path: "api.go",
+				Templates: []*T{
+					{Name: "init", Require: []string{"Domain"}, Strategy: StrategyInit, Template: initTmpl},
+					{Name: "get", Strategy: StrategyAppendAtPos, Template: "func Get() {}\n"},
+					{Name: "post", Strategy: StrategyAppendAtPos, Template: postTmpl},
+				},
+			}
+		}
+		seq = NewSeq("api", "init", "get", "post")
+	)
+
+	c := cli.NewConsole()
+	c.SetOutput(io.Discard)
+	c.SetInput(strings.NewReader("user\n"))
+
+	g := NewWithConfig(C().SetSilent(true).SetStorePath(storePath))
+	g.c = c
+	g.SetDecls(newDecl("package {{ .Domain }}\n", "func Post() { {{ .Broken }\n"))
+	assert.NotNil(t, g.Build(seq))
+	assert.Len(t, g.States(), 2)
+
+	// the built states are restored, so the changed init template is not used
+	// and the domain is not asked again.
+	resumed := NewWithConfig(C().SetSilent(true).SetStorePath(storePath).SetInteractive(false))
+	resumed.SetDecls(newDecl("package changed\n", "func Post() {}\n"))
+	assert.Nil(t, resumed.Resume(g.BuildID()))
+
+	tmpls := []string{}
+	for _, s := range resumed.States() {
+		tmpls = append(tmpls, s.ParsedTmpl)
+	}
+	assert.Equal(t, []string{"package user\n", "func Get() {}\n", "func Post() {}\n"}, tmpls)
+
+	states, err := LoadStates(filepath.Join(storePath, g.BuildID(), "state"))
+	assert.Nil(t, err)
+	assert.Len(t, states, 3)
+
+	// a different sequence can not be resumed.
+	other := NewWithConfig(C().SetSilent(true).SetStorePath(storePath).SetInteractive(false))
+	other.SetDecls(newDecl("package changed\n", "func Post() {}\n"))
+	other.resumed = states
+	other.UpdateArgs(Args{"Domain": "user"})
+	assert.ErrorContains(t, other.Build(NewSeq("api", "init", "post")), "state 1 is 'api.get', but the sequence builds 'api.post'")
+
+	// the done nodes are restored before their args and conditions, so the
+	// changed declaration asks nothing and selects the same cases.
+	newBranchDecl := func(require []string, viewIf, kind, postTmpl string) *D {
+		return &D{
+			Name: "svc",
+			Path: "svc.go",
+			Args: Args{"Kind": kind},
+			Templates: []*T{
+				{Name: "init", Require: require, Strategy: StrategyInit, Template: "package svc\n"},
+				{Name: "view", Require: []string{"View"}, Strategy: StrategyAppendAtPos, If: viewIf, Template: "// view\n"},
+				{Name: "crud", Strategy: StrategyAppendAtPos, Template: "// crud\n"},
+				{Name: "readonly", Strategy: StrategyAppendAtPos, Template: "// readonly\n"},
+				{Name: "post", Strategy: StrategyAppendAtPos, Template: postTmpl},
+			},
+		}
+	}
+	branchSeq := NewSeq("svc", "init", "view").SelectBy("Kind", "svc", []string{"crud", "readonly"}, func(ss SeqSwitcher) {
+		ss.When("crud", func(c *Seq) *Seq {
+			return c.Append("svc", "post")
+		})
+	})
+
+	storePath = t.TempDir()
+	g = NewWithConfig(C().SetSilent(true).SetStorePath(storePath).SetInteractive(false))
+	g.SetDecls(newBranchDecl(nil, ".WithView", "crud", "{{ .Broken }"))
+	assert.NotNil(t, g.Build(branchSeq))
+
+	resumed = NewWithConfig(C().SetSilent(true).SetStorePath(storePath).SetInteractive(false))
+	resumed.SetDecls(newBranchDecl([]string{"Extra"}, "", "readonly", "// post\n"))
+	assert.Nil(t, resumed.Resume(g.BuildID()))
+
+	assert.Equal(t, []string{"init", "crud", "post"}, builtENames(resumed))
+}
+
+// TestApplyFrom tests applying the saved states of a build by another
+// instance.
+func TestApplyFrom(t *testing.T) {
+	var (
+		dir       = testlib.CreateDir(t)
+		storePath = t.TempDir()
+		path      = filepath.Join(dir, "api.go")
+	)
+
+	g := NewWithConfig(C().SetSilent(true).SetStorePath(storePath).SetInteractive(false))
+	g.SetDecls(&D{
+		Name: "api",
+		Path: path,
+		Templates: []*T{
+			{Name: "init", Strategy: StrategyInit, Template: "package {{ .Domain }}\n// +gojen:append=handler\n"},
+			{Name: "get", Strategy: StrategyAppend, Alias: "handler", Template: "func Get() {}\n"},
+			{Name: "post", Strategy: StrategyAppend, Alias: "handler", Template: "func Post() {}\n"},
+		},
+	})
+	g.UpdateArgs(Args{"Domain": "user"})
+	assert.Nil(t, g.Build(NewSeq("api", "init", "get", "post")))
+
+	// another instance applies the saved states without the declarations.
+	applied := NewWithConfig(C().SetSilent(true).SetStorePath(storePath).SetInteractive(false))
+	assert.Nil(t, applied.ApplyFrom(filepath.Join(storePath, g.BuildID(), "state")))
+
+	b, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "package user\n// +gojen:append=handler\nfunc Post() {}\n\nfunc Get() {}\n\n", string(b))
+
+	assert.ErrorContains(t, applied.ApplyFrom(t.TempDir()), "no states found")
+}
+
+// TestErrors tests the structured errors of a build and the positions of the
+// template errors.
+func TestErrors(t *testing.T) {
+	g := NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()).SetInteractive(false))
+	g.SetDecls(&D{
+		Name:   "api",
+		Path:   "api.go",
+		source: "decls/api.yaml",
+		Templates: []*T{
+			{Name: "init", Require: []string{"Domain"}, Strategy: StrategyInit},
+			{Name: "broken", Strategy: StrategyAppendAtPos, Template: "package api\n\nfunc {{ .Name | unknown }}() {}\n"},
+			{Name: "failing", Strategy: StrategyAppendAtPos, Template: "{{ index .List 3 }}"},
+			{Name: "guarded", Strategy: StrategyAppendAtPos, If: "index .List 3"},
+		},
+	})
+
+	err := g.Build(NewSeq("unknown", "init"))
+	assert.ErrorIs(t, err, &ErrDeclNotFound{})
+	assert.ErrorIs(t, err, &ErrDeclNotFound{DName: "unknown"})
+	assert.NotErrorIs(t, err, &ErrDeclNotFound{DName: "api"})
+
+	err = g.Build(NewSeq("api", "missing"))
+	assert.ErrorIs(t, err, &ErrElementNotFound{DName: "api", EName: "missing"})
+
+	err = g.Build(NewSeq("api", "init"))
+	var mErr *ErrMissingArg
+	assert.ErrorAs(t, err, &mErr)
+	assert.Equal(t, []string{"Domain"}, mErr.Args)
+
+	_, err = g.Seq("unknown")
+	assert.ErrorIs(t, err, &ErrSeqNotFound{Name: "unknown"})
+
+	err = g.Build(NewSeq("api", "broken"))
+	var tErr *ErrTemplate
+	assert.ErrorAs(t, err, &tErr)
+	assert.Equal(t, "content", tErr.Name)
+	assert.Equal(t, "decls/api.yaml", tErr.Source)
+	assert.Equal(t, 3, tErr.Line)
+	assert.EqualError(t, err, `invalid content of 'api.broken' (decls/api.yaml, line 3): function "unknown" not defined`)
+	assert.Equal(t, "  2 | \n> 3 | func {{ .Name | unknown }}() {}\n  4 | \n", tErr.Snippet(1))
+
+	g.UpdateArgs(Args{"List": []any{}})
+	err = g.Build(NewSeq("api", "failing"))
+	assert.ErrorAs(t, err, &tErr)
+	assert.Equal(t, 1, tErr.Line)
+	assert.Equal(t, 3, tErr.Column)
+	assert.Equal(t, "> 1 | {{ index .List 3 }}\n    |   ^\n", tErr.Snippet(1))
+
+	// the position of a condition is in the condition with its delimiters.
+	err = g.Build(NewSeq("api", "guarded"))
+	assert.ErrorAs(t, err, &tErr)
+	assert.Equal(t, "condition", tErr.Name)
+	assert.Equal(t, 3, tErr.Column)
+	assert.Equal(t, "> 1 | {{ index .List 3 }}\n    |   ^\n", tErr.Snippet(1))
+
+	err = g.Validate(NewSeq("api", "init", "missing"))
+	assert.ErrorIs(t, err, &ErrMissingArg{EName: "init"})
+	assert.ErrorIs(t, err, &ErrElementNotFound{EName: "missing"})
+}
+
+// TestKeepGoing tests collecting the errors of all failing nodes.
+func TestKeepGoing(t *testing.T) {
+	g := NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()).SetInteractive(false).SetKeepGoing(true))
+	g.SetDecls(&D{
+		Name: "api",
+		Path: "api.go",
+		Templates: []*T{
+			{Name: "init", Strategy: StrategyInit},
+			{Name: "get", Require: []string{"Domain"}, Strategy: StrategyAppendAtPos},
+			{Name: "post", Strategy: StrategyAppendAtPos, Template: "{{ .Name | unknown }}"},
+			{Name: "delete", Strategy: StrategyAppendAtPos},
+		},
+	})
+
+	err := g.Build(NewSeq("api", "init", "get", "missing", "post", "delete"))
+	var bErr *ErrBuild
+	assert.ErrorAs(t, err, &bErr)
+	assert.Len(t, bErr.Errs, 3)
+	assert.ErrorIs(t, err, &ErrMissingArg{EName: "get"})
+	assert.ErrorIs(t, err, &ErrElementNotFound{EName: "missing"})
+	var tErr *ErrTemplate
+	assert.ErrorAs(t, err, &tErr)
+	assert.Equal(t, "post", tErr.EName)
+	assert.Contains(t, err.Error(), "build failed with 3 errors:\n  - api.get: missing required arguments [Domain] of 'api.get'")
+
+	// the reachable nodes are built, but nothing is applied.
+	assert.Equal(t, []string{"init", "delete"}, builtENames(g))
+	assert.ErrorIs(t, g.Apply(), err)
+}
+
+// TestMissingKey tests rendering, reporting or failing on the missing args of
+// the templates.
+func TestMissingKey(t *testing.T) {
+	newGojen := func(mode MissingKey, args Args) *Gojen {
+		g := NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()).SetInteractive(false).SetMissingKey(mode))
+		g.SetDecls(&D{
+			Name: "api",
+			Path: "{{ .Domain }}.go",
+			Templates: []*T{
+				{Name: "init", Strategy: StrategyInit, If: ".WithInit", Template: "package {{ .Domian }}\n// {{ .Note }}\n"},
+			},
+		})
+		g.UpdateArgs(args)
+		return g
+	}
+
+	g := newGojen(MissingKeyZero, Args{"Domain": "user", "WithInit": true})
+	assert.Nil(t, g.Build(NewSeq("api", "init")))
+	assert.Equal(t, "package <no value>\n// <no value>\n", g.States()[0].ParsedTmpl)
+	assert.Empty(t, g.States()[0].MissingArgs)
+
+	g = newGojen(MissingKeyWarn, Args{"Domain": "user", "WithInit": true})
+	assert.Nil(t, g.Build(NewSeq("api", "init")))
+	assert.Equal(t, "package <no value>\n// <no value>\n", g.States()[0].ParsedTmpl)
+	assert.Equal(t, []string{"Domian", "Note"}, g.States()[0].MissingArgs)
+
+	g = newGojen(MissingKeyError, Args{"Domain": "user", "WithInit": true})
+	err := g.Build(NewSeq("api", "init"))
+	var tErr *ErrTemplate
+	assert.ErrorAs(t, err, &tErr)
+	assert.Equal(t, 1, tErr.Line)
+	assert.Contains(t, err.Error(), `map has no entry for key "Domian"`)
+
+	// the conditions are not strict.
+	g = newGojen(MissingKeyError, Args{"Domain": "user"})
+	assert.Nil(t, g.Build(NewSeq("api", "init")))
+	assert.Empty(t, g.States())
+
+	mk, err := ParseMissingKey("warn")
+	assert.Nil(t, err)
+	assert.Equal(t, MissingKeyWarn, mk)
+	_, err = ParseMissingKey("strict")
+	assert.EqualError(t, err, "invalid missing key 'strict', expected zero, error or warn")
+	err = NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()).SetMissingKey("strict")).Build(NewSeq("api", "init"))
+	assert.EqualError(t, err, "invalid missing key 'strict', expected zero, error or warn")
+}
+
+// TestDelims tests rendering the templates with custom delimiters and raw
+// blocks.
+func TestDelims(t *testing.T) {
+	g := NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()).SetInteractive(false))
+	g.SetDecls(&D{
+		Name:   "chart",
+		Path:   "[[ .Name ]].yaml",
+		Delims: []string{"[[", "]]"},
+		Templates: []*T{
+			{Name: "values", Strategy: StrategyInit, If: ".Name", Template: "name: [[ .Name ]]\nimage: {{ .Values.image }}\n"},
+			{Name: "vue", Path: "<% .Name %>.vue", Delims: []string{"<%", "%>"}, Strategy: StrategyInit, Template: "<p>{{ msg }}</p><% .Name %>\n"},
+			{Name: "raw", Path: "raw.go", Strategy: StrategyInit, Delims: []string{"{{", "}}"}, Template: "{{ raw }}{{ .Kept }}\n[[ .Kept ]]{{ /raw }}{{ .Name }}\n{{ .Bad"},
+		},
+	})
+	g.UpdateArgs(Args{"Name": "api"})
+
+	assert.Nil(t, g.Build(NewSeq("chart", "values", "vue")))
+	states := g.States()
+	assert.Equal(t, "api.yaml", states[0].ParsedPath)
+	assert.Equal(t, "name: api\nimage: {{ .Values.image }}\n", states[0].ParsedTmpl)
+	assert.Equal(t, "api.vue", states[1].ParsedPath)
+	assert.Equal(t, "<p>{{ msg }}</p>api\n", states[1].ParsedTmpl)
+
+	// the line numbers after a raw block are kept.
+	err := g.Build(NewSeq("chart", "raw"))
+	var tErr *ErrTemplate
+	assert.ErrorAs(t, err, &tErr)
+	assert.Equal(t, 3, tErr.Line)
+
+	g.Decl("chart").GetElements("raw").Template = "{{ raw }}{{ .Kept }}\n[[ .Kept ]]{{ /raw }}{{ .Name }}\n"
+	assert.Nil(t, g.Build(NewSeq("chart", "raw")))
+	assert.Equal(t, "{{ .Kept }}\n[[ .Kept ]]api\n", g.States()[2].ParsedTmpl)
+
+	// the condition of a step uses the default delimiters, the condition of
+	// the element uses its delimiters.
+	g = NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()).SetInteractive(false))
+	g.SetDecls(&D{
+		Name:      "chart",
+		Path:      "[[ .Name ]].yaml",
+		Delims:    []string{"[[", "]]"},
+		Templates: []*T{{Name: "values", Strategy: StrategyInit, If: "[[ .Name ]]"}},
+	})
+	g.UpdateArgs(Args{"Name": "api"})
+	assert.Nil(t, g.Build(NewSeq("chart", "values").If("{{ .On }}")))
+	assert.Empty(t, g.States())
+	g.UpdateArgs(Args{"On": true})
+	assert.Nil(t, g.Build(NewSeq("chart", "values").If("{{ .On }}")))
+	assert.Len(t, g.States(), 1)
+
+	assert.ErrorContains(t, (&D{Name: "d", Path: "p", Delims: []string{"[["}, Templates: []*T{{Name: "e"}}}).Validate(), "delims must be a pair")
+}
+
+// TestTemplateFiles tests loading the templates of the elements and the
+// outputs from their own files.
+func TestTemplateFiles(t *testing.T) {
+	dir := testlib.CreateDir(t, "decls")
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "templates"), os.ModePerm))
+	testlib.NewFileWithContent(t, filepath.Join(dir, "templates", "handler.go.tmpl"), "func {{ .Method }}() {}\n\n{{ end }}\n")
+	testlib.NewFileWithContent(t, filepath.Join(dir, "templates", "route.go.tmpl"), "// route {{ .Method }}\n{{ end }}\n")
+	testlib.NewFileWithContent(t, filepath.Join(dir, "api.yaml"), `
+name: api
+path: api.go
+elements:
+  - name: handler
+    template_file: templates/handler.go.tmpl
+    strategy: append_at_pos
+    output:
+      route:
+        template_file: templates/route.go.tmpl
+`)
+
+	g := NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()).SetInteractive(false))
+	assert.Nil(t, g.LoadDecls(dir))
+
+	e := g.Decl("api").GetElements("handler")
+	assert.Equal(t, "func {{ .Method }}() {}\n\n{{ end }}\n", e.Template)
+	assert.Equal(t, "// route {{ .Method }}\n{{ end }}\n", e.Output["route"].Template)
+
+	// the errors point to the template files.
+	var tErr *ErrTemplate
+	assert.ErrorAs(t, g.Build(NewSeq("api", "handler")), &tErr)
+	assert.Equal(t, filepath.Join(dir, "templates", "handler.go.tmpl"), tErr.Source)
+	assert.Equal(t, 3, tErr.Line)
+
+	e.Template = "func {{ .Method }}() {}\n"
+	assert.ErrorAs(t, g.Build(NewSeq("api", "handler")), &tErr)
+	assert.Equal(t, "content of output 'route'", tErr.Name)
+	assert.Equal(t, filepath.Join(dir, "templates", "route.go.tmpl"), tErr.Source)
+	assert.Equal(t, 2, tErr.Line)
+
+	testlib.NewFileWithContent(t, filepath.Join(dir, "api.yaml"), `
+name: api
+path: api.go
+elements:
+  - name: handler
+    template_file: templates/missing.go.tmpl
+`)
+	err := NewWithConfig(C().SetSilent(true)).LoadDecls(dir)
+	assert.ErrorContains(t, err, "element 'handler': error reading template file")
+
+	// the template files of the declarations which are set directly are
+	// loaded by the build.
+	g = NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()).SetInteractive(false))
+	g.SetDecls(&D{
+		Name: "api",
+		Path: "api.go",
+		Templates: []*T{
+			{
+				Name:         "handler",
+				TemplateFile: filepath.Join(dir, "templates", "route.go.tmpl"),
+				Strategy:     StrategyAppendAtPos,
+				Args:         Args{"Method": "get"},
+			},
+		},
+	})
+	assert.ErrorAs(t, g.Build(NewSeq("api", "handler")), &tErr)
+	assert.Equal(t, filepath.Join(dir, "templates", "route.go.tmpl"), tErr.Source)
+
+	g = NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()).SetInteractive(false))
+	g.SetDecls(&D{
+		Name:      "api",
+		Path:      "api.go",
+		Templates: []*T{{Name: "handler", TemplateFile: "missing.go.tmpl", Strategy: StrategyAppendAtPos}},
+	})
+	err = g.Build(NewSeq("api", "handler"))
+	assert.ErrorContains(t, err, "declaration 'api': element 'handler': error reading template file")
+}
+
+// TestFrontMatter tests loading the elements declared by the front matter of
+// template files.
+func TestFrontMatter(t *testing.T) {
+	dir := testlib.CreateDir(t, "api")
+	testlib.NewFileWithContent(t, filepath.Join(dir, "api.yaml"), `
+name: api
+path: api/{{ .Name }}.go
+require: [Name]
+elements:
+  - name: init
+    template: |
+      package api
+  - name: deploy
+    path: deploy.yaml
+    template_file: deploy.tmpl
+`)
+	testlib.NewFileWithContent(t, filepath.Join(dir, "handler.gojen.tmpl"), `---
+name: handler
+strategy: append_at_pos
+require: [Method]
+output:
+  route:
+    path: api/routes.go
+    template_file: route.tmpl
+---
+func {{ .Method }}() {}
+`)
+	testlib.NewFileWithContent(t, filepath.Join(dir, "model.gojen.tmpl"), `---
+decl: model
+name: init
+path: model/{{ .Name }}.go
+---
+package model
+
+{{ end }}
+`)
+	// the template files of the elements are not declarations, even if they
+	// start with '---'.
+	testlib.NewFileWithContent(t, filepath.Join(dir, "deploy.tmpl"), "---\nkind: Deployment\n---\nkind: Service\n")
+	testlib.NewFileWithContent(t, filepath.Join(dir, "route.tmpl"), "---\n// route {{ .Method }}\n")
+
+	g := NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()).SetInteractive(false))
+	assert.Nil(t, g.LoadDecls(dir))
+
+	// the elements are added to the declaration of the same name.
+	api := g.Decl("api")
+	assert.Len(t, api.Templates, 3)
+	assert.Equal(t, "---\nkind: Deployment\n---\nkind: Service\n", api.GetElements("deploy").Template)
+	e := api.GetElements("handler")
+	assert.Equal(t, "func {{ .Method }}() {}\n", e.Template)
+	assert.Equal(t, StrategyAppendAtPos, e.Strategy)
+	assert.Equal(t, []string{"Method"}, e.Require)
+	assert.Equal(t, "---\n// route {{ .Method }}\n", e.Output["route"].Template)
+
+	// or to a new declaration.
+	model := g.Decl("model")
+	assert.NotNil(t, model)
+	assert.Len(t, model.Templates, 1)
+	assert.Equal(t, "package model\n\n{{ end }}\n", model.Templates[0].Template)
+
+	// the error lines are the lines of the file.
+	var tErr *ErrTemplate
+	assert.ErrorAs(t, g.Build(NewSeq("model", "init")), &tErr)
+	assert.Equal(t, filepath.Join(dir, "model.gojen.tmpl"), tErr.Source)
+	assert.Equal(t, 8, tErr.Line)
+	assert.Equal(t, "  7 | \n> 8 | {{ end }}\n  9 | \n", tErr.Snippet(1))
+
+	testlib.NewFileWithContent(t, filepath.Join(dir, "init.gojen.tmpl"), "---\nname: init\n---\npackage api\n")
+	err := NewWithConfig(C().SetSilent(true)).LoadDecls(dir)
+	assert.ErrorContains(t, err, "element 'init' is already declared in")
+
+	testlib.NewFileWithContent(t, filepath.Join(dir, "init.gojen.tmpl"), "---\nname: init\n")
+	err = NewWithConfig(C().SetSilent(true)).LoadDecls(dir)
+	assert.ErrorContains(t, err, "front matter is not closed by '---'")
+
+	testlib.NewFileWithContent(t, filepath.Join(dir, "init.gojen.tmpl"), "package api\n")
+	err = NewWithConfig(C().SetSilent(true)).LoadDecls(dir)
+	assert.ErrorContains(t, err, "front matter is required")
+}
+
+// newInteractiveGojen returns a silent Gojen with the declaration, whose
+// console reads the input. It is interactive unless the input is empty.
+func newInteractiveGojen(t *testing.T, input string, d *D) *Gojen {
+	c := cli.NewConsole()
+	c.SetOutput(io.Discard)
+	c.SetInput(strings.NewReader(input))
+
+	g := NewWithConfig(C().SetSilent(true).SetStorePath(t.TempDir()).SetInteractive(input != ""))
+	g.c = c
+	g.SetDecls(d)
+	return g
+}
+
+// builtENames returns the element names of the built states. The name of a
+// state built for an item of a loop is suffixed by ':<item>'.
+func builtENames(g *Gojen) []string {
+	res := []string{}
+	for _, s := range g.States() {
+		name := s.EName
+		if s.seq != nil && s.seq.LoopAs != "" {
+			name += fmt.Sprintf(":%v", s.Args[s.seq.LoopAs])
+		}
+		res = append(res, name)
+	}
+	return res
+}
@@ -0,0 +1,203 @@
+package gojen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cirius-go/gojen/util"
+)
+
+type (
+	// graphNode is a node of the graph of a sequence.
+	graphNode struct {
+		id       string
+		label    []string
+		isSelect bool
+	}
+
+	// graphEdge is an edge of the graph of a sequence.
+	graphEdge struct {
+		from, to string
+		label    []string
+	}
+
+	// seqGraph is the graph of a sequence, the nodes which are shared by the
+	// cases of a multi-select are added once.
+	seqGraph struct {
+		nodes []*graphNode
+		edges []*graphEdge
+	}
+)
+
+// Mermaid returns the sequence as a Mermaid flowchart. The edges are labeled
+// with the selected case, the forwarded args and the temp args.
+func (s *Seq) Mermaid() string {
+	g := newSeqGraph(s.root)
+
+	b := strings.Builder{}
+	b.WriteString("flowchart TD\n")
+	for _, n := range g.nodes {
+		label := mermaidEscape(strings.Join(n.label, "<br/>"))
+		if n.isSelect {
+			fmt.Fprintf(&b, "  %s{\"%s\"}\n", n.id, label)
+			continue
+		}
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", n.id, label)
+	}
+	for _, e := range g.edges {
+		if len(e.label) == 0 {
+			fmt.Fprintf(&b, "  %s --> %s\n", e.from, e.to)
+			continue
+		}
+		fmt.Fprintf(&b, "  %s -->|\"%s\"| %s\n", e.from, mermaidEscape(strings.Join(e.label, "<br/>")), e.to)
+	}
+
+	return b.String()
+}
+
+// DOT returns the sequence as a Graphviz DOT digraph. The edges are labeled
+// with the selected case, the forwarded args and the temp args.
+func (s *Seq) DOT() string {
+	g := newSeqGraph(s.root)
+
+	b := strings.Builder{}
+	b.WriteString("digraph seq {\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, n := range g.nodes {
+		label := dotEscape(strings.Join(n.label, "\n"))
+		if n.isSelect {
+			fmt.Fprintf(&b, "  %s [label=\"%s\", shape=diamond];\n", n.id, label)
+			continue
+		}
+		fmt.Fprintf(&b, "  %s [label=\"%s\"];\n", n.id, label)
+	}
+	for _, e := range g.edges {
+		if len(e.label) == 0 {
+			fmt.Fprintf(&b, "  %s -> %s;\n", e.from, e.to)
+			continue
+		}
+		fmt.Fprintf(&b, "  %s -> %s [label=\"%s\"];\n", e.from, e.to, dotEscape(strings.Join(e.label, "\n")))
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// newSeqGraph creates the graph of the sequence starting from the root.
+func newSeqGraph(root *Seq) *seqGraph {
+	var (
+		g       = &seqGraph{}
+		ids     = root.nodeIDs()
+		visited = map[*Seq]bool{}
+		visit   func(n *Seq) string
+	)
+
+	// the edges are added before their target is visited, so they are in the
+	// same order as the nodes.
+	visit = func(n *Seq) string {
+		id := ids[n]
+		if visited[n] {
+			return id
+		}
+		visited[n] = true
+
+		g.nodes = append(g.nodes, &graphNode{id: id, label: n.graphLabel(), isSelect: len(n.Cases) > 0})
+
+		if len(n.Cases) == 0 {
+			if n.Next != nil {
+				e := &graphEdge{from: id, label: n.edgeLabel(n.Next, "")}
+				g.edges = append(g.edges, e)
+				e.to = visit(n.Next)
+			}
+			return id
+		}
+
+		util.LoopStrMap(n.Cases, func(eName string, c *Seq) {
+			caseLabel := "case " + eName
+			if n.SelectArg != "" {
+				caseLabel = fmt.Sprintf("%s=%s", n.SelectArg, eName)
+			}
+			if n.DefaultCase == eName {
+				caseLabel += " (default)"
+			}
+			e := &graphEdge{from: id, label: n.edgeLabel(c, caseLabel)}
+			g.edges = append(g.edges, e)
+			e.to = visit(c)
+		})
+
+		return id
+	}
+	visit(root)
+
+	// the temp args of the root have no edge to be shown on.
+	if len(root.TempArgs) > 0 {
+		g.nodes[0].label = append(g.nodes[0].label, tempArgsLabel(root.TempArgs))
+	}
+
+	return g
+}
+
+// graphLabel returns the lines of the label of the node.
+func (s *Seq) graphLabel() []string {
+	res := []string{fmt.Sprintf("%s.%s", s.DName, s.EName)}
+	if s.LoopArg != "" {
+		res = append(res, fmt.Sprintf("for %s in %s", s.LoopAs, s.LoopArg))
+	}
+	if s.Cond != "" {
+		res = append(res, fmt.Sprintf("if %s", s.Cond))
+	}
+	switch {
+	case s.SelectArg != "":
+		res = append(res, fmt.Sprintf("select by %s", s.SelectArg))
+	case s.MultiSelect:
+		res = append(res, "select multiple")
+	}
+
+	return res
+}
+
+// edgeLabel returns the lines of the label of the edge from the node to the
+// next node.
+func (s *Seq) edgeLabel(next *Seq, caseLabel string) []string {
+	res := []string{}
+	if caseLabel != "" {
+		res = append(res, caseLabel)
+	}
+	if len(s.ForwardArgs) > 0 {
+		keys := s.ForwardArgs.Keys()
+		sort.Strings(keys)
+		res = append(res, "forward: "+strings.Join(keys, ", "))
+	}
+	if len(next.TempArgs) > 0 {
+		res = append(res, tempArgsLabel(next.TempArgs))
+	}
+
+	return res
+}
+
+// tempArgsLabel returns the temp args as 'with: k=v, ...' sorted by key.
+func tempArgsLabel(args Args) string {
+	keys := make([]string, 0, len(args))
+	for k := range args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	with := make([]string, 0, len(keys))
+	for _, k := range keys {
+		with = append(with, fmt.Sprintf("%s=%v", k, args[k]))
+	}
+
+	return "with: " + strings.Join(with, ", ")
+}
+
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
+
+func dotEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
@@ -18,10 +18,21 @@ type (
 		FileExists(path string) bool
 		AppendContent(path string, content string) error
 		AppendContentAfter(path string, lineIdent, content string) error
+		PrependContent(path string, content string) error
+		PrependContentBefore(path string, lineIdent, content string) error
+		ReplaceContentAt(path string, startIdent, endIdent, content string) error
 		CompareFile(src, dst string, ignoreLines util.MapExisting[string]) (percent float64, dstHighlighted string, err error)
 		CompareContentWithFile(content, dst string, ignoreLines util.MapExisting[string]) (percent float64, dstHighlighted string, err error)
 	}
 
+	// StagedFileManager is a FileManager which keeps all writes in memory
+	// until they are committed.
+	StagedFileManager interface {
+		FileManager
+		Changes() []*filemanager.Change
+		Commit() error
+	}
+
 	// ConsoleManager is an interface that defines the methods for interacting with the
 	// console.
 	//go:generate mockery --name ConsoleManager
@@ -51,7 +62,11 @@ type (
 	StoreManager interface {
 		LoadDir(dirPath string) error
 		GetDecl(name string) *D
+		GetDecls() []*D
 		SetDecl(d *D) bool
+		GetSeq(name string) *S
+		GetSeqs() []*S
+		SetSeq(s *S) bool
 		GetArgs(keys ...string) (Args, []string)
 		UpdateArgs(args Args)
 		AddState(s *State)
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"time"
 
 	"golang.org/x/term"
@@ -39,8 +40,9 @@ func (c *Config) WithDelayReadInput(delay time.Duration) *Config {
 type Console struct {
 	cfg *Config
 
-	writer io.Writer
-	reader io.Reader
+	writer  io.Writer
+	reader  io.Reader
+	scanner *bufio.Scanner // keeps the buffered input between reads.
 }
 
 // C returns a new CLIConfig instance.
@@ -52,9 +54,9 @@ func C() *Config {
 
 func NewWithConfig(cfg *Config) *Console {
 	return &Console{
-		cfg,
-		os.Stdout,
-		os.Stdin,
+		cfg:    cfg,
+		writer: os.Stdout,
+		reader: os.Stdin,
 	}
 }
 
@@ -68,6 +70,7 @@ func NewConsole() *Console {
 // SetInput sets the input reader for the cli.
 func (c *Console) SetInput(reader io.Reader) {
 	c.reader = reader
+	c.scanner = nil
 }
 
 // SetOutput sets the output writer for the cli.
@@ -136,15 +139,21 @@ func (c *Console) SuccessStringf(msg string, args ...any) string {
 	return c.sprintf(greenColor, msg, args...)
 }
 
-// Scanln scans the input from the user.
+// Scanln scans a line of the input from the user. It returns io.EOF if there
+// is no more input.
 func (c *Console) Scanln() ([]byte, error) {
-	scanner := bufio.NewScanner(c.reader)
-	scanner.Scan()
-	if err := scanner.Err(); err != nil {
-		return nil, err
+	if c.scanner == nil {
+		c.scanner = bufio.NewScanner(c.reader)
 	}
 
-	return scanner.Bytes(), nil
+	if !c.scanner.Scan() {
+		if err := c.scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+
+	return c.scanner.Bytes(), nil
 }
 
 // TermWidth returns the terminal width.
@@ -167,12 +176,12 @@ func (c *Console) PerformYesNo(msg string, args ...any) bool {
 		time.Sleep(c.cfg.readInputDelay)
 	}
 
-	var input string
-	if _, err := fmt.Fscanln(c.reader, &input); err != nil {
+	b, err := c.Scanln()
+	if err != nil {
 		return false
 	}
 
-	switch input {
+	switch strings.TrimSpace(string(b)) {
 	case "y", "Y", "yes", "YES", "true", "TRUE", "1":
 		return true
 	default:
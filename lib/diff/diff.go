@@ -0,0 +1,169 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultContext is the number of unchanged lines printed around changes.
+const DefaultContext = 3
+
+type (
+	opKind int
+
+	// op is an edit operation of a single line.
+	op struct {
+		kind opKind
+		line string
+		a, b int // line index in a and b.
+	}
+)
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+// Unified returns the unified diff between a and b. It returns an empty
+// string if both contents are equal.
+func Unified(aName, bName, a, b string, context int) string {
+	if a == b {
+		return ""
+	}
+
+	var (
+		aLines = splitLines(a)
+		bLines = splitLines(b)
+		ops    = compute(aLines, bLines)
+		w      = strings.Builder{}
+	)
+
+	fmt.Fprintf(&w, "--- %s\n", aName)
+	fmt.Fprintf(&w, "+++ %s\n", bName)
+
+	for _, h := range hunks(ops, context) {
+		writeHunk(&w, ops[h[0]:h[1]])
+	}
+
+	return w.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// compute returns the edit operations to transform a into b using the
+// longest common subsequence of lines.
+func compute(a, b []string) []op {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+				continue
+			}
+			lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+		}
+	}
+
+	ops := make([]op, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, op{opEqual, a[i], i, j})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, op{opDelete, a[i], i, j})
+			i++
+		default:
+			ops = append(ops, op{opInsert, b[j], i, j})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, op{opDelete, a[i], i, j})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, op{opInsert, b[j], i, j})
+	}
+
+	return ops
+}
+
+// hunks returns the [start, end) ranges of ops which should be printed.
+func hunks(ops []op, context int) [][2]int {
+	res := [][2]int{}
+	for i, o := range ops {
+		if o.kind == opEqual {
+			continue
+		}
+
+		start, end := max(i-context, 0), min(i+context+1, len(ops))
+		if len(res) > 0 && start <= res[len(res)-1][1] {
+			res[len(res)-1][1] = end
+			continue
+		}
+		res = append(res, [2]int{start, end})
+	}
+
+	return res
+}
+
+func writeHunk(w *strings.Builder, ops []op) {
+	var (
+		aStart, bStart = ops[0].a, ops[0].b
+		aCount, bCount = 0, 0
+		body           = strings.Builder{}
+	)
+
+	for _, o := range ops {
+		prefix := " "
+		switch o.kind {
+		case opEqual:
+			aCount++
+			bCount++
+		case opDelete:
+			prefix = "-"
+			aCount++
+		case opInsert:
+			prefix = "+"
+			bCount++
+		}
+
+		body.WriteString(prefix + o.line)
+		if !strings.HasSuffix(o.line, "\n") {
+			body.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+
+	fmt.Fprintf(w, "@@ -%s +%s @@\n", hunkRange(aStart, aCount), hunkRange(bStart, bCount))
+	w.WriteString(body.String())
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
@@ -18,6 +18,7 @@ type (
 	FileManager struct {
 		cfg        *Config
 		builtFiles map[string]string
+		staged     map[string]*Change // nil if the writes go to disk directly.
 	}
 )
 
@@ -100,16 +101,13 @@ func (f *FileManager) WalkDir(dirPath string, openFile bool, handler func(e *Fil
 // CreateIfNotExist creates a file with the given content if it does not exist.
 func (f *FileManager) CreateFileIfNotExist(path string, content string) (created bool, err error) {
 	dir, _ := filepath.Split(path)
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
-			return false, err
-		}
+	if err := f.mkdirAll(dir); err != nil {
+		return false, err
 	}
 
 	// Check if file exists
-	if _, err = os.Stat(path); os.IsNotExist(err) {
-		err = os.WriteFile(path, []byte(content), 0644)
-		if err != nil {
+	if !f.exists(path) {
+		if err = f.writeFile(path, []byte(content)); err != nil {
 			return false, err
 		}
 		return true, nil
@@ -121,39 +119,29 @@ func (f *FileManager) CreateFileIfNotExist(path string, content string) (created
 
 // TruncWithContent truncates the file with the given content.
 func (f *FileManager) TruncWithContent(path string, content string) error {
-	return os.WriteFile(path, []byte(content), 0644)
+	return f.writeFile(path, []byte(content))
 }
 
 // FileExists checks if the file exists.
 func (f *FileManager) FileExists(path string) bool {
-	stat, err := os.Stat(path)
-	if err != nil {
-		return false
-	}
-
-	if stat.IsDir() {
-		return false
-	}
-
-	return true
+	return f.exists(path)
 }
 
 // AppendContent appends the content to the file.
 func (f *FileManager) AppendContent(path string, content string) error {
-	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
+	fileContent, err := f.readFile(path)
+	if err != nil && !os.IsNotExist(err) {
 		return err
 	}
-	defer file.Close()
-	_, err = file.WriteString(content)
-	return err
+
+	return f.writeFile(path, append(fileContent, content...))
 }
 
 // AppendContentAfter appends the content after the line identified by lineIdent.
 func (f *FileManager) AppendContentAfter(path string, lineIdent, content string) error {
 	lineIdent = strings.TrimSpace(lineIdent)
 	// Read the entire file
-	fileContent, err := os.ReadFile(path)
+	fileContent, err := f.readFile(path)
 	if err != nil {
 		return fmt.Errorf("error reading file: %w", err)
 	}
@@ -181,7 +169,7 @@ func (f *FileManager) AppendContentAfter(path string, lineIdent, content string)
 	newContent := strings.Join(newLines, "\n")
 
 	// Write the modified contents back to the file
-	err = os.WriteFile(path, []byte(newContent), 0644)
+	err = f.writeFile(path, []byte(newContent))
 	if err != nil {
 		return fmt.Errorf("error writing to file: %w", err)
 	}
@@ -189,29 +177,118 @@ func (f *FileManager) AppendContentAfter(path string, lineIdent, content string)
 	return nil
 }
 
-func (f *FileManager) CopyFile(src, dst string) error {
-	srcFile, err := os.Open(src)
+// PrependContent prepends the content at the head of the file.
+func (f *FileManager) PrependContent(path string, content string) error {
+	fileContent, err := f.readFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error reading file: %w", err)
+	}
+
+	err = f.writeFile(path, append([]byte(content), fileContent...))
 	if err != nil {
-		return err
+		return fmt.Errorf("error writing to file: %w", err)
 	}
-	defer srcFile.Close()
 
-	dstFile, err := os.Create(dst)
+	return nil
+}
+
+// PrependContentBefore inserts the content before the line identified by
+// lineIdent.
+func (f *FileManager) PrependContentBefore(path string, lineIdent, content string) error {
+	lineIdent = strings.TrimSpace(lineIdent)
+	fileContent, err := f.readFile(path)
 	if err != nil {
-		return err
+		return fmt.Errorf("error reading file: %w", err)
+	}
+
+	lines := strings.Split(string(fileContent), "\n")
+
+	newLines := []string{}
+	identFound := false
+	for _, line := range lines {
+		if strings.TrimSpace(line) == lineIdent {
+			newLines = append(newLines, content)
+			identFound = true
+		}
+		newLines = append(newLines, line)
+	}
+
+	// If lineIdent is not found, return without modifying the file
+	if !identFound {
+		return nil
 	}
-	defer dstFile.Close()
 
-	_, err = srcFile.WriteTo(dstFile)
+	newContent := strings.Join(newLines, "\n")
+
+	err = f.writeFile(path, []byte(newContent))
 	if err != nil {
-		return err
+		return fmt.Errorf("error writing to file: %w", err)
 	}
 
 	return nil
 }
 
+// ReplaceContentAt replaces the lines between the line identified by
+// startIdent and the line identified by endIdent with the content. Both
+// identified lines are kept so the content can be replaced again.
+func (f *FileManager) ReplaceContentAt(path string, startIdent, endIdent, content string) error {
+	startIdent = strings.TrimSpace(startIdent)
+	endIdent = strings.TrimSpace(endIdent)
+	fileContent, err := f.readFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading file: %w", err)
+	}
+
+	lines := strings.Split(string(fileContent), "\n")
+
+	newLines := []string{}
+	startFound, replacing := false, false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if replacing {
+			if trimmed != endIdent {
+				continue
+			}
+			replacing = false
+		}
+
+		newLines = append(newLines, line)
+		if trimmed == startIdent {
+			newLines = append(newLines, content)
+			startFound, replacing = true, true
+		}
+	}
+
+	// If startIdent is not found, return without modifying the file
+	if !startFound {
+		return nil
+	}
+
+	if replacing {
+		return fmt.Errorf("line '%s' not found after '%s' in '%s'", endIdent, startIdent, path)
+	}
+
+	newContent := strings.Join(newLines, "\n")
+
+	err = f.writeFile(path, []byte(newContent))
+	if err != nil {
+		return fmt.Errorf("error writing to file: %w", err)
+	}
+
+	return nil
+}
+
+func (f *FileManager) CopyFile(src, dst string) error {
+	content, err := f.readFile(src)
+	if err != nil {
+		return err
+	}
+
+	return f.writeFile(dst, content)
+}
+
 func (f *FileManager) getLinesFromFile(path string) (map[string]bool, []string, error) {
-	content, err := os.ReadFile(path)
+	content, err := f.readFile(path)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -0,0 +1,186 @@
+package filemanager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cirius-go/gojen/util"
+)
+
+// Change contains the content of a staged file before and after the writes.
+type Change struct {
+	Path    string
+	Before  string
+	After   string
+	Created bool
+}
+
+// NewStaged returns a new staged file manager instance.
+func NewStaged() *FileManager {
+	c := C()
+	return NewStagedWithConfig(c)
+}
+
+// NewStagedWithConfig returns a file manager which keeps all writes in memory
+// on top of the files on disk. The disk is never written.
+func NewStagedWithConfig(c *Config) *FileManager {
+	f := NewWithConfig(c)
+	f.staged = make(map[string]*Change)
+	return f
+}
+
+// Changes returns the staged changes sorted by path. Files which are written
+// with their original content are omitted.
+func (f *FileManager) Changes() []*Change {
+	res := make([]*Change, 0, len(f.staged))
+	util.LoopStrMap(f.staged, func(_ string, c *Change) {
+		if !c.Created && c.Before == c.After {
+			return
+		}
+		res = append(res, c)
+	})
+
+	return res
+}
+
+func (f *FileManager) readFile(path string) ([]byte, error) {
+	if f.staged != nil {
+		if c, ok := f.staged[filepath.Clean(path)]; ok {
+			return []byte(c.After), nil
+		}
+	}
+
+	return os.ReadFile(path)
+}
+
+func (f *FileManager) writeFile(path string, content []byte) error {
+	if f.staged == nil {
+		return os.WriteFile(path, content, 0644)
+	}
+
+	path = filepath.Clean(path)
+	if c, ok := f.staged[path]; ok {
+		c.After = string(content)
+		return nil
+	}
+
+	before, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	f.staged[path] = &Change{
+		Path:    path,
+		Before:  string(before),
+		After:   string(content),
+		Created: os.IsNotExist(err),
+	}
+
+	return nil
+}
+
+func (f *FileManager) exists(path string) bool {
+	if f.staged != nil {
+		if _, ok := f.staged[filepath.Clean(path)]; ok {
+			return true
+		}
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	return !stat.IsDir()
+}
+
+func (f *FileManager) mkdirAll(dir string) error {
+	if f.staged != nil || dir == "" {
+		return nil
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return os.MkdirAll(dir, os.ModePerm)
+	}
+
+	return nil
+}
+
+// Commit writes the staged changes to disk. Each file is written to a
+// temporary file and renamed over the target. If any file fails, the files
+// committed before are restored to their original content and the created
+// files are removed.
+func (f *FileManager) Commit() (err error) {
+	var (
+		changes   = f.Changes()
+		committed = make([]*Change, 0, len(changes))
+	)
+
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		for i := len(committed) - 1; i >= 0; i-- {
+			c := committed[i]
+			if c.Created {
+				_ = os.Remove(c.Path)
+				continue
+			}
+			_ = atomicWrite(c.Path, []byte(c.Before))
+		}
+	}()
+
+	for _, c := range changes {
+		dir, _ := filepath.Split(c.Path)
+		if dir != "" {
+			if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+				return fmt.Errorf("error committing '%s': %w", c.Path, err)
+			}
+		}
+
+		if err := atomicWrite(c.Path, []byte(c.After)); err != nil {
+			return fmt.Errorf("error committing '%s': %w", c.Path, err)
+		}
+		committed = append(committed, c)
+	}
+
+	return nil
+}
+
+// atomicWrite writes the content to a temporary file in the same directory
+// and renames it to path.
+func atomicWrite(path string, content []byte) error {
+	var (
+		dir, name = filepath.Split(path)
+		mode      = os.FileMode(0644)
+	)
+
+	if stat, err := os.Stat(path); err == nil {
+		mode = stat.Mode().Perm()
+	}
+
+	if dir == "" {
+		dir = "."
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+name+".gojen-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
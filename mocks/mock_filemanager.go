@@ -3,8 +3,11 @@
 package gojen
 
 import (
-	gojen "github.com/cirius-go/gojen"
+	filemanager "github.com/cirius-go/gojen/lib/filemanager"
+
 	mock "github.com/stretchr/testify/mock"
+
+	util "github.com/cirius-go/gojen/util"
 )
 
 // FileManager is an autogenerated mock type for the FileManager type
@@ -67,6 +70,184 @@ func (_c *FileManager_AppendContent_Call) RunAndReturn(run func(string, string)
 	return _c
 }
 
+// AppendContentAfter provides a mock function with given fields: path, lineIdent, content
+func (_m *FileManager) AppendContentAfter(path string, lineIdent string, content string) error {
+	ret := _m.Called(path, lineIdent, content)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AppendContentAfter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(path, lineIdent, content)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FileManager_AppendContentAfter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AppendContentAfter'
+type FileManager_AppendContentAfter_Call struct {
+	*mock.Call
+}
+
+// AppendContentAfter is a helper method to define mock.On call
+//   - path string
+//   - lineIdent string
+//   - content string
+func (_e *FileManager_Expecter) AppendContentAfter(path interface{}, lineIdent interface{}, content interface{}) *FileManager_AppendContentAfter_Call {
+	return &FileManager_AppendContentAfter_Call{Call: _e.mock.On("AppendContentAfter", path, lineIdent, content)}
+}
+
+func (_c *FileManager_AppendContentAfter_Call) Run(run func(path string, lineIdent string, content string)) *FileManager_AppendContentAfter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *FileManager_AppendContentAfter_Call) Return(_a0 error) *FileManager_AppendContentAfter_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *FileManager_AppendContentAfter_Call) RunAndReturn(run func(string, string, string) error) *FileManager_AppendContentAfter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CompareContentWithFile provides a mock function with given fields: content, dst, ignoreLines
+func (_m *FileManager) CompareContentWithFile(content string, dst string, ignoreLines util.MapExisting[string]) (float64, string, error) {
+	ret := _m.Called(content, dst, ignoreLines)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompareContentWithFile")
+	}
+
+	var r0 float64
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, string, util.MapExisting[string]) (float64, string, error)); ok {
+		return rf(content, dst, ignoreLines)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, util.MapExisting[string]) float64); ok {
+		r0 = rf(content, dst, ignoreLines)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, util.MapExisting[string]) string); ok {
+		r1 = rf(content, dst, ignoreLines)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(string, string, util.MapExisting[string]) error); ok {
+		r2 = rf(content, dst, ignoreLines)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FileManager_CompareContentWithFile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompareContentWithFile'
+type FileManager_CompareContentWithFile_Call struct {
+	*mock.Call
+}
+
+// CompareContentWithFile is a helper method to define mock.On call
+//   - content string
+//   - dst string
+//   - ignoreLines util.MapExisting[string]
+func (_e *FileManager_Expecter) CompareContentWithFile(content interface{}, dst interface{}, ignoreLines interface{}) *FileManager_CompareContentWithFile_Call {
+	return &FileManager_CompareContentWithFile_Call{Call: _e.mock.On("CompareContentWithFile", content, dst, ignoreLines)}
+}
+
+func (_c *FileManager_CompareContentWithFile_Call) Run(run func(content string, dst string, ignoreLines util.MapExisting[string])) *FileManager_CompareContentWithFile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(util.MapExisting[string]))
+	})
+	return _c
+}
+
+func (_c *FileManager_CompareContentWithFile_Call) Return(percent float64, dstHighlighted string, err error) *FileManager_CompareContentWithFile_Call {
+	_c.Call.Return(percent, dstHighlighted, err)
+	return _c
+}
+
+func (_c *FileManager_CompareContentWithFile_Call) RunAndReturn(run func(string, string, util.MapExisting[string]) (float64, string, error)) *FileManager_CompareContentWithFile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CompareFile provides a mock function with given fields: src, dst, ignoreLines
+func (_m *FileManager) CompareFile(src string, dst string, ignoreLines util.MapExisting[string]) (float64, string, error) {
+	ret := _m.Called(src, dst, ignoreLines)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompareFile")
+	}
+
+	var r0 float64
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, string, util.MapExisting[string]) (float64, string, error)); ok {
+		return rf(src, dst, ignoreLines)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, util.MapExisting[string]) float64); ok {
+		r0 = rf(src, dst, ignoreLines)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, util.MapExisting[string]) string); ok {
+		r1 = rf(src, dst, ignoreLines)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(string, string, util.MapExisting[string]) error); ok {
+		r2 = rf(src, dst, ignoreLines)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FileManager_CompareFile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompareFile'
+type FileManager_CompareFile_Call struct {
+	*mock.Call
+}
+
+// CompareFile is a helper method to define mock.On call
+//   - src string
+//   - dst string
+//   - ignoreLines util.MapExisting[string]
+func (_e *FileManager_Expecter) CompareFile(src interface{}, dst interface{}, ignoreLines interface{}) *FileManager_CompareFile_Call {
+	return &FileManager_CompareFile_Call{Call: _e.mock.On("CompareFile", src, dst, ignoreLines)}
+}
+
+func (_c *FileManager_CompareFile_Call) Run(run func(src string, dst string, ignoreLines util.MapExisting[string])) *FileManager_CompareFile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(util.MapExisting[string]))
+	})
+	return _c
+}
+
+func (_c *FileManager_CompareFile_Call) Return(percent float64, dstHighlighted string, err error) *FileManager_CompareFile_Call {
+	_c.Call.Return(percent, dstHighlighted, err)
+	return _c
+}
+
+func (_c *FileManager_CompareFile_Call) RunAndReturn(run func(string, string, util.MapExisting[string]) (float64, string, error)) *FileManager_CompareFile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateFileIfNotExist provides a mock function with given fields: path, content
 func (_m *FileManager) CreateFileIfNotExist(path string, content string) (bool, error) {
 	ret := _m.Called(path, content)
@@ -170,6 +351,150 @@ func (_c *FileManager_FileExists_Call) RunAndReturn(run func(string) bool) *File
 	return _c
 }
 
+// PrependContent provides a mock function with given fields: path, content
+func (_m *FileManager) PrependContent(path string, content string) error {
+	ret := _m.Called(path, content)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PrependContent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(path, content)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FileManager_PrependContent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PrependContent'
+type FileManager_PrependContent_Call struct {
+	*mock.Call
+}
+
+// PrependContent is a helper method to define mock.On call
+//   - path string
+//   - content string
+func (_e *FileManager_Expecter) PrependContent(path interface{}, content interface{}) *FileManager_PrependContent_Call {
+	return &FileManager_PrependContent_Call{Call: _e.mock.On("PrependContent", path, content)}
+}
+
+func (_c *FileManager_PrependContent_Call) Run(run func(path string, content string)) *FileManager_PrependContent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *FileManager_PrependContent_Call) Return(_a0 error) *FileManager_PrependContent_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *FileManager_PrependContent_Call) RunAndReturn(run func(string, string) error) *FileManager_PrependContent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PrependContentBefore provides a mock function with given fields: path, lineIdent, content
+func (_m *FileManager) PrependContentBefore(path string, lineIdent string, content string) error {
+	ret := _m.Called(path, lineIdent, content)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PrependContentBefore")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(path, lineIdent, content)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FileManager_PrependContentBefore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PrependContentBefore'
+type FileManager_PrependContentBefore_Call struct {
+	*mock.Call
+}
+
+// PrependContentBefore is a helper method to define mock.On call
+//   - path string
+//   - lineIdent string
+//   - content string
+func (_e *FileManager_Expecter) PrependContentBefore(path interface{}, lineIdent interface{}, content interface{}) *FileManager_PrependContentBefore_Call {
+	return &FileManager_PrependContentBefore_Call{Call: _e.mock.On("PrependContentBefore", path, lineIdent, content)}
+}
+
+func (_c *FileManager_PrependContentBefore_Call) Run(run func(path string, lineIdent string, content string)) *FileManager_PrependContentBefore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *FileManager_PrependContentBefore_Call) Return(_a0 error) *FileManager_PrependContentBefore_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *FileManager_PrependContentBefore_Call) RunAndReturn(run func(string, string, string) error) *FileManager_PrependContentBefore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReplaceContentAt provides a mock function with given fields: path, startIdent, endIdent, content
+func (_m *FileManager) ReplaceContentAt(path string, startIdent string, endIdent string, content string) error {
+	ret := _m.Called(path, startIdent, endIdent, content)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplaceContentAt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string) error); ok {
+		r0 = rf(path, startIdent, endIdent, content)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FileManager_ReplaceContentAt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReplaceContentAt'
+type FileManager_ReplaceContentAt_Call struct {
+	*mock.Call
+}
+
+// ReplaceContentAt is a helper method to define mock.On call
+//   - path string
+//   - startIdent string
+//   - endIdent string
+//   - content string
+func (_e *FileManager_Expecter) ReplaceContentAt(path interface{}, startIdent interface{}, endIdent interface{}, content interface{}) *FileManager_ReplaceContentAt_Call {
+	return &FileManager_ReplaceContentAt_Call{Call: _e.mock.On("ReplaceContentAt", path, startIdent, endIdent, content)}
+}
+
+func (_c *FileManager_ReplaceContentAt_Call) Run(run func(path string, startIdent string, endIdent string, content string)) *FileManager_ReplaceContentAt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *FileManager_ReplaceContentAt_Call) Return(_a0 error) *FileManager_ReplaceContentAt_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *FileManager_ReplaceContentAt_Call) RunAndReturn(run func(string, string, string, string) error) *FileManager_ReplaceContentAt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // TruncWithContent provides a mock function with given fields: path, content
 func (_m *FileManager) TruncWithContent(path string, content string) error {
 	ret := _m.Called(path, content)
@@ -218,7 +543,7 @@ func (_c *FileManager_TruncWithContent_Call) RunAndReturn(run func(string, strin
 }
 
 // WalkDir provides a mock function with given fields: dirPath, openFile, handler
-func (_m *FileManager) WalkDir(dirPath string, openFile bool, handler func(*gojen.FileInfo) error) error {
+func (_m *FileManager) WalkDir(dirPath string, openFile bool, handler func(*filemanager.FileInfo) error) error {
 	ret := _m.Called(dirPath, openFile, handler)
 
 	if len(ret) == 0 {
@@ -226,7 +551,7 @@ func (_m *FileManager) WalkDir(dirPath string, openFile bool, handler func(*goje
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, bool, func(*gojen.FileInfo) error) error); ok {
+	if rf, ok := ret.Get(0).(func(string, bool, func(*filemanager.FileInfo) error) error); ok {
 		r0 = rf(dirPath, openFile, handler)
 	} else {
 		r0 = ret.Error(0)
@@ -243,14 +568,14 @@ type FileManager_WalkDir_Call struct {
 // WalkDir is a helper method to define mock.On call
 //   - dirPath string
 //   - openFile bool
-//   - handler func(*gojen.FileInfo) error
+//   - handler func(*filemanager.FileInfo) error
 func (_e *FileManager_Expecter) WalkDir(dirPath interface{}, openFile interface{}, handler interface{}) *FileManager_WalkDir_Call {
 	return &FileManager_WalkDir_Call{Call: _e.mock.On("WalkDir", dirPath, openFile, handler)}
 }
 
-func (_c *FileManager_WalkDir_Call) Run(run func(dirPath string, openFile bool, handler func(*gojen.FileInfo) error)) *FileManager_WalkDir_Call {
+func (_c *FileManager_WalkDir_Call) Run(run func(dirPath string, openFile bool, handler func(*filemanager.FileInfo) error)) *FileManager_WalkDir_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(bool), args[2].(func(*gojen.FileInfo) error))
+		run(args[0].(string), args[1].(bool), args[2].(func(*filemanager.FileInfo) error))
 	})
 	return _c
 }
@@ -260,7 +585,7 @@ func (_c *FileManager_WalkDir_Call) Return(_a0 error) *FileManager_WalkDir_Call
 	return _c
 }
 
-func (_c *FileManager_WalkDir_Call) RunAndReturn(run func(string, bool, func(*gojen.FileInfo) error) error) *FileManager_WalkDir_Call {
+func (_c *FileManager_WalkDir_Call) RunAndReturn(run func(string, bool, func(*filemanager.FileInfo) error) error) *FileManager_WalkDir_Call {
 	_c.Call.Return(run)
 	return _c
 }
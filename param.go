@@ -0,0 +1,280 @@
+package gojen
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cirius-go/gojen/util"
+)
+
+// ParamType is the type of an argument.
+type ParamType string
+
+const (
+	// ParamTypeString is a string argument.
+	ParamTypeString ParamType = "string"
+	// ParamTypeInt is an integer argument.
+	ParamTypeInt ParamType = "int"
+	// ParamTypeBool is a boolean argument.
+	ParamTypeBool ParamType = "bool"
+	// ParamTypeList is a list argument. A string is split by comma.
+	ParamTypeList ParamType = "list"
+	// ParamTypeMap is a map argument.
+	ParamTypeMap ParamType = "map"
+	// ParamTypeEnum is a string argument which must be one of the values.
+	ParamTypeEnum ParamType = "enum"
+)
+
+type (
+	// Param represents the schema of an argument.
+	Param struct {
+		Type        ParamType `json:"type" yaml:"type"`
+		Default     any       `json:"default" yaml:"default"`
+		Pattern     string    `json:"pattern" yaml:"pattern"` // regex which a string argument must match.
+		Values      []string  `json:"values" yaml:"values"`   // values of an enum argument.
+		Description string    `json:"description" yaml:"description"`
+	}
+
+	// Params is a map of argument schemas. Key is the argument name.
+	Params map[string]*Param
+)
+
+func (p *Param) Validate() error {
+	switch p.Type {
+	case "", ParamTypeString, ParamTypeInt, ParamTypeBool, ParamTypeList, ParamTypeMap:
+	case ParamTypeEnum:
+		if len(p.Values) == 0 {
+			return fmt.Errorf("values are required for enum")
+		}
+	default:
+		return fmt.Errorf("unknown type '%s'", p.Type)
+	}
+
+	if _, err := p.pattern(); err != nil {
+		return err
+	}
+
+	if p.Default != nil {
+		if _, err := p.Coerce(p.Default); err != nil {
+			return fmt.Errorf("invalid default: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Coerce converts the value to the type of the param and validates it.
+func (p *Param) Coerce(v any) (any, error) {
+	var (
+		res any
+		err error
+	)
+
+	switch p.Type {
+	case "", ParamTypeString:
+		res, err = toString(v)
+	case ParamTypeInt:
+		res, err = toInt(v)
+	case ParamTypeBool:
+		res, err = toBool(v)
+	case ParamTypeList:
+		res, err = toList(v)
+	case ParamTypeMap:
+		res, err = toMap(v)
+	case ParamTypeEnum:
+		var s string
+		if s, err = toString(v); err == nil && !slices.Contains(p.Values, s) {
+			err = fmt.Errorf("'%s' is not one of [%s]", s, strings.Join(p.Values, ", "))
+		}
+		res = s
+	default:
+		return nil, fmt.Errorf("unknown type '%s'", p.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s, ok := res.(string); ok && p.Pattern != "" {
+		re, err := p.pattern()
+		if err != nil {
+			return nil, err
+		}
+		if !re.MatchString(s) {
+			return nil, fmt.Errorf("'%s' does not match pattern '%s'", s, p.Pattern)
+		}
+	}
+
+	return res, nil
+}
+
+// pattern compiles the pattern of the param, it is nil if the param has no
+// pattern. The params which are not loaded from a file are not validated, so
+// the pattern may be invalid.
+func (p *Param) pattern() (*regexp.Regexp, error) {
+	if p.Pattern == "" {
+		return nil, nil
+	}
+
+	re, err := regexp.Compile(p.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	return re, nil
+}
+
+// Validate validates the schemas of all arguments.
+func (ps Params) Validate() error {
+	var err error
+	util.LoopStrMap(ps, func(k string, p *Param) {
+		if err != nil {
+			return
+		}
+		if e := p.Validate(); e != nil {
+			err = fmt.Errorf("param '%s': %w", k, e)
+		}
+	})
+
+	return err
+}
+
+// Merge returns the params merged with the given params. The later params
+// override the earlier ones.
+func (ps Params) Merge(pss ...Params) Params {
+	res := make(Params, len(ps))
+	for k, p := range ps {
+		res[k] = p
+	}
+	for _, n := range pss {
+		for k, p := range n {
+			res[k] = p
+		}
+	}
+
+	return res
+}
+
+// Defaults returns the default values of the arguments which are not in args.
+func (ps Params) Defaults(args Args) Args {
+	res := make(Args)
+	for k, p := range ps {
+		if _, ok := args[k]; ok || p.Default == nil {
+			continue
+		}
+		res[k] = p.Default
+	}
+
+	return res
+}
+
+// Coerce converts the arguments which have a schema to their types and
+// validates them.
+func (ps Params) Coerce(args Args) error {
+	var err error
+	util.LoopStrMap(ps, func(k string, p *Param) {
+		v, ok := args[k]
+		if err != nil || !ok {
+			return
+		}
+
+		cv, e := p.Coerce(v)
+		if e != nil {
+			err = fmt.Errorf("argument '%s': %w", k, e)
+			return
+		}
+		args[k] = cv
+	})
+
+	return err
+}
+
+func toString(v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case int, int64, float64, bool:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("expected string, got %T", v)
+	}
+}
+
+func toInt(v any) (int, error) {
+	switch v := v.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v != float64(int(v)) {
+			return 0, fmt.Errorf("expected int, got %v", v)
+		}
+		return int(v), nil
+	case string:
+		i, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return 0, fmt.Errorf("expected int, got '%s'", v)
+		}
+		return i, nil
+	default:
+		return 0, fmt.Errorf("expected int, got %T", v)
+	}
+}
+
+func toBool(v any) (bool, error) {
+	switch v := v.(type) {
+	case bool:
+		return v, nil
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return false, fmt.Errorf("expected bool, got '%s'", v)
+		}
+		return b, nil
+	default:
+		return false, fmt.Errorf("expected bool, got %T", v)
+	}
+}
+
+func toList(v any) ([]any, error) {
+	switch v := v.(type) {
+	case []any:
+		return v, nil
+	case []string:
+		res := make([]any, 0, len(v))
+		for _, s := range v {
+			res = append(res, s)
+		}
+		return res, nil
+	case string:
+		res := []any{}
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				res = append(res, s)
+			}
+		}
+		return res, nil
+	default:
+		return nil, fmt.Errorf("expected list, got %T", v)
+	}
+}
+
+func toMap(v any) (map[string]any, error) {
+	switch v := v.(type) {
+	case map[string]any:
+		return v, nil
+	case Args:
+		return v, nil
+	case map[any]any:
+		res := make(map[string]any, len(v))
+		for k, val := range v {
+			res[fmt.Sprint(k)] = val
+		}
+		return res, nil
+	default:
+		return nil, fmt.Errorf("expected map, got %T", v)
+	}
+}
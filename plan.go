@@ -0,0 +1,112 @@
+package gojen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cirius-go/gojen/lib/diff"
+	"github.com/cirius-go/gojen/lib/filemanager"
+)
+
+type (
+	// FilePlan contains the planned change of a file.
+	FilePlan struct {
+		Path    string
+		Created bool
+		Diff    string // unified diff.
+	}
+
+	// Plan contains the planned changes of all files touched by the built
+	// states.
+	Plan struct {
+		Files []*FilePlan
+	}
+)
+
+// Created returns the paths of the files which would be created.
+func (p *Plan) Created() []string {
+	res := []string{}
+	for _, f := range p.Files {
+		if f.Created {
+			res = append(res, f.Path)
+		}
+	}
+	return res
+}
+
+// Modified returns the paths of the existing files which would be modified.
+func (p *Plan) Modified() []string {
+	res := []string{}
+	for _, f := range p.Files {
+		if !f.Created {
+			res = append(res, f.Path)
+		}
+	}
+	return res
+}
+
+// Summary returns the summary of created and modified files.
+func (p *Plan) Summary() string {
+	w := strings.Builder{}
+	fmt.Fprintf(&w, "Plan: %d to create, %d to modify.\n", len(p.Created()), len(p.Modified()))
+	for _, f := range p.Files {
+		mark := "~"
+		if f.Created {
+			mark = "+"
+		}
+		fmt.Fprintf(&w, "  %s %s\n", mark, f.Path)
+	}
+
+	return w.String()
+}
+
+// String returns the summary followed by the diffs of all files.
+func (p *Plan) String() string {
+	w := strings.Builder{}
+	w.WriteString(p.Summary())
+	for _, f := range p.Files {
+		w.WriteString("\n")
+		w.WriteString(f.Diff)
+	}
+
+	return w.String()
+}
+
+// newPlan creates a plan from the staged changes.
+func newPlan(changes []*filemanager.Change) *Plan {
+	p := &Plan{
+		Files: make([]*FilePlan, 0, len(changes)),
+	}
+
+	for _, c := range changes {
+		path := diffPath(c.Path)
+		from := "a/" + path
+		if c.Created {
+			from = "/dev/null"
+		}
+
+		p.Files = append(p.Files, &FilePlan{
+			Path:    c.Path,
+			Created: c.Created,
+			Diff:    diff.Unified(from, "b/"+path, c.Before, c.After, diff.DefaultContext),
+		})
+	}
+
+	return p
+}
+
+// diffPath returns the path of the file in the diff headers, so the diff can
+// be used by 'git apply' or 'patch -p1'. The path is relative to the working
+// directory if the file is in it, otherwise the root is removed.
+func diffPath(path string) string {
+	if wd, err := os.Getwd(); err == nil && filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(wd, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.ToSlash(rel)
+		}
+	}
+
+	path = strings.TrimPrefix(path, filepath.VolumeName(path))
+	return strings.TrimLeft(filepath.ToSlash(filepath.Clean(path)), "/")
+}
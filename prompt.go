@@ -0,0 +1,275 @@
+package gojen
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cirius-go/gojen/util"
+)
+
+// askArgs asks for the missing required arguments of the node with the given
+// id. The answers
+// are used first, then the defaults in non-interactive mode. In interactive
+// mode, each argument is asked individually until a valid value is given.
+// Every answer is recorded into the build directory.
+func (g *Gojen) askArgs(n *Seq, node string, names []string, params Params) (Args, error) {
+	sort.Strings(names)
+
+	var (
+		res     = make(Args, len(names))
+		missing = []string{}
+	)
+	for _, k := range names {
+		if v, ok := g.cfg.answers.Arg(node, k); ok {
+			res[k] = v
+			continue
+		}
+		if p, ok := params[k]; ok && p.Default != nil && !g.cfg.interactive {
+			res[k] = p.Default
+			continue
+		}
+		missing = append(missing, k)
+	}
+
+	if len(missing) == 0 {
+		return res, g.recordArgs(node, res)
+	}
+
+	if !g.cfg.interactive {
+		return nil, &ErrMissingArg{DName: n.DName, EName: n.EName, Args: missing}
+	}
+
+	g.c.Dangerf(true, "Please provide the missing arguments of '%s.%s':\n", n.DName, n.EName)
+	for _, k := range missing {
+		v, err := g.askArg(k, params[k])
+		if err != nil {
+			return nil, err
+		}
+		res[k] = v
+	}
+
+	return res, g.recordArgs(node, res)
+}
+
+// askArg asks for the value of an argument until it is valid for the param.
+func (g *Gojen) askArg(name string, p *Param) (any, error) {
+	if p == nil {
+		p = &Param{}
+	}
+
+	pType := util.IfValue(ParamTypeString, p.Type)
+	g.c.Infof(true, "%s (%s)", name, pType)
+	if p.Description != "" {
+		g.c.Printf(true, ": %s", p.Description)
+	}
+	g.c.Printf(true, "\n")
+	for i, v := range p.Values {
+		g.c.Printf(true, "  %d) %s\n", i+1, v)
+	}
+
+	for {
+		if p.Default != nil {
+			g.c.Dangerf(true, "%s [%v]: ", name, p.Default)
+		} else {
+			g.c.Dangerf(true, "%s: ", name)
+		}
+
+		b, err := g.c.Scanln()
+		if err != nil {
+			return nil, fmt.Errorf("error reading argument '%s': %w", name, err)
+		}
+
+		input := strings.TrimSpace(string(b))
+		if input == "" {
+			if p.Default != nil {
+				return p.Default, nil
+			}
+			g.c.Warnf(true, "Argument '%s' is required\n", name)
+			continue
+		}
+
+		var v any = input
+		switch pType {
+		case ParamTypeEnum:
+			if i, err := strconv.Atoi(input); err == nil && i >= 1 && i <= len(p.Values) {
+				v = p.Values[i-1]
+			}
+		case ParamTypeList, ParamTypeMap:
+			if strings.HasPrefix(input, "[") || strings.HasPrefix(input, "{") {
+				if err := json.Unmarshal(b, &v); err != nil {
+					g.c.Warnf(true, "Invalid JSON: %s\n", err)
+					continue
+				}
+			}
+		}
+
+		cv, err := p.Coerce(v)
+		if err != nil {
+			g.c.Warnf(true, "Invalid value of '%s': %s\n", name, err)
+			continue
+		}
+
+		return cv, nil
+	}
+}
+
+// askCase asks which cases of the node with the given id should be built.
+// Cases are selected by number or element name, several cases are separated
+// by comma if the node allows multi-select. If the node selects by an arg which is present in
+// args, its value selects the cases. Otherwise the answers are used first,
+// then the default case in non-interactive mode.
+func (g *Gojen) askCase(n *Seq, node string, args Args) ([]*Seq, error) {
+	if v, ok := args[n.SelectArg]; ok && n.SelectArg != "" {
+		selected, err := caseValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("argument '%s' of '%s.%s': %w", n.SelectArg, n.DName, n.EName, err)
+		}
+
+		cases, err := n.selectCases(selected, nil)
+		if err != nil {
+			return nil, fmt.Errorf("argument '%s': %w", n.SelectArg, err)
+		}
+		return cases, g.recordCase(node, cases)
+	}
+
+	if selected, ok := g.cfg.answers.Case(node, n.DName, n.EName); ok {
+		cases, err := n.selectCases(selected, nil)
+		if err != nil {
+			return nil, err
+		}
+		return cases, g.recordCase(node, cases)
+	}
+
+	if !g.cfg.interactive {
+		if n.DefaultCase == "" {
+			return nil, fmt.Errorf("case of '%s.%s' is not answered in non-interactive mode", n.DName, n.EName)
+		}
+
+		cases, err := n.selectCases(n.DefaultCase, nil)
+		if err != nil {
+			return nil, err
+		}
+		return cases, g.recordCase(node, cases)
+	}
+
+	if n.MultiSelect {
+		g.c.Dangerf(true, "Which cases do you want to choose (separated by comma)?\n")
+	} else {
+		g.c.Dangerf(true, "Which case do you want to choose?\n")
+	}
+	i := 1
+	mapIndexCases := map[int]*Seq{}
+	util.LoopStrMap(n.Cases, func(k string, c *Seq) {
+		g.c.Infof(true, "%d) %s.%s\n", i, c.DName, c.EName)
+		mapIndexCases[i] = c
+		i++
+	})
+
+	for {
+		if n.DefaultCase != "" {
+			g.c.Dangerf(true, "Select case [%s]: ", n.DefaultCase)
+		} else {
+			g.c.Dangerf(true, "Select case: ")
+		}
+
+		selectedBytes, err := g.c.Scanln()
+		if err != nil {
+			return nil, err
+		}
+
+		selected := strings.TrimSpace(string(selectedBytes))
+		if selected == "" {
+			selected = n.DefaultCase
+		}
+
+		cases, err := n.selectCases(selected, mapIndexCases)
+		if err != nil {
+			g.c.Warnf(true, "%s\n", err)
+			continue
+		}
+
+		return cases, g.recordCase(node, cases)
+	}
+}
+
+// caseValue converts the value of a select arg to comma separated element
+// names.
+func caseValue(v any) (string, error) {
+	if s, err := toString(v); err == nil {
+		return s, nil
+	}
+
+	l, err := toList(v)
+	if err != nil {
+		return "", fmt.Errorf("expected element name or list of element names, got %T", v)
+	}
+
+	names := make([]string, 0, len(l))
+	for _, item := range l {
+		s, err := toString(item)
+		if err != nil {
+			return "", fmt.Errorf("expected element name, got %T", item)
+		}
+		names = append(names, s)
+	}
+
+	return strings.Join(names, ","), nil
+}
+
+// selectCases returns the cases of the node selected by the comma separated
+// element names or indexes.
+func (s *Seq) selectCases(selected string, indexes map[int]*Seq) ([]*Seq, error) {
+	var (
+		res  = []*Seq{}
+		seen = map[*Seq]bool{}
+	)
+
+	for _, sel := range strings.Split(selected, ",") {
+		sel = strings.TrimSpace(sel)
+		if sel == "" {
+			continue
+		}
+
+		c, ok := s.Cases[sel]
+		if !ok {
+			if i, err := strconv.Atoi(sel); err == nil {
+				c, ok = indexes[i]
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("invalid case '%s' selected in '%s.%s'", sel, s.DName, s.EName)
+		}
+
+		if !seen[c] {
+			seen[c] = true
+			res = append(res, c)
+		}
+	}
+
+	if len(res) == 0 {
+		return nil, fmt.Errorf("no case selected in '%s.%s'", s.DName, s.EName)
+	}
+	if len(res) > 1 && !s.MultiSelect {
+		return nil, fmt.Errorf("only one case can be selected in '%s.%s'", s.DName, s.EName)
+	}
+
+	return res, nil
+}
+
+// confirm asks the yes/no question about the subject. The answers are used
+// first, the user is only asked in interactive mode.
+func (g *Gojen) confirm(q Question, subject string, msg string, args ...any) (bool, error) {
+	if ok, answered := g.cfg.answers.Confirmed(q, subject); answered {
+		return ok, g.recordConfirm(q, subject, ok)
+	}
+
+	if !g.cfg.interactive {
+		return false, fmt.Errorf("question '%s' about '%s' is not answered in non-interactive mode", q, subject)
+	}
+
+	ok := g.c.PerformYesNo(msg, args...)
+	return ok, g.recordConfirm(q, subject, ok)
+}
@@ -0,0 +1,129 @@
+package gojen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const recordingFileName = "recording.yaml"
+
+// Recording contains a build session, which can be replayed to reproduce the
+// same build without any input.
+type Recording struct {
+	// Args are the args of the store when the build started.
+	Args Args `yaml:"args"`
+	// Seq is the built sequence, each node has its id. A node which is shared
+	// by several cases is written once, the others refer to its id.
+	Seq *Seq `yaml:"seq"`
+	// Answers contains every answer given during load, build and apply. The
+	// answers of the nodes are keyed by their ids.
+	Answers *Answers `yaml:"answers"`
+	// Nodes are the ids of the nodes of Seq which are built or skipped, in the
+	// order they are done.
+	Nodes []string `yaml:"nodes,omitempty"`
+}
+
+// LoadRecording loads the recording of the given build in the store path.
+func LoadRecording(storePath, buildID string) (*Recording, error) {
+	path := filepath.Join(storePath, buildID, recordingFileName)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("build '%s' has no recording", buildID)
+		}
+		return nil, err
+	}
+
+	rec := &Recording{}
+	if err := yaml.Unmarshal(b, rec); err != nil {
+		return nil, fmt.Errorf("error parsing recording '%s': %w", path, err)
+	}
+
+	if rec.Seq == nil {
+		return nil, fmt.Errorf("recording '%s' has no sequence", path)
+	}
+	if err := rec.Seq.unref(); err != nil {
+		return nil, fmt.Errorf("error parsing recording '%s': %w", path, err)
+	}
+	rec.Seq.link(rec.Seq, SeqC())
+
+	if rec.Answers == nil {
+		rec.Answers = NewAnswers()
+	}
+
+	return rec, nil
+}
+
+// Replay builds the recorded sequence with the recorded args. Every question
+// is answered by the recording, the build fails on any other question.
+func (g *Gojen) Replay(rec *Recording) error {
+	g.cfg.answers = rec.Answers
+	g.cfg.interactive = false
+	g.UpdateArgs(rec.Args)
+
+	return g.Build(rec.Seq)
+}
+
+// recordSeq records the sequence and the args of the store when a build
+// starts.
+func (g *Gojen) recordSeq(seq *Seq) error {
+	args, _ := g.s.GetArgs()
+	g.recording.Args = args.Clone()
+	g.recording.Seq = seq.recorded()
+	g.recording.Nodes = nil
+
+	return g.saveRecording()
+}
+
+// recordNode records the node of the sequence as done, it is built or
+// skipped.
+func (g *Gojen) recordNode(id string) error {
+	g.recording.Nodes = append(g.recording.Nodes, id)
+
+	return g.saveRecording()
+}
+
+// recordArgs records the answered args of the node with the given id.
+func (g *Gojen) recordArgs(node string, args Args) error {
+	na := g.recording.Answers.node(node)
+	na.Args = NewArgs(na.Args, args)
+
+	return g.saveRecording()
+}
+
+// recordCase records the selected cases of the node with the given id.
+func (g *Gojen) recordCase(node string, cases []*Seq) error {
+	eNames := make([]string, 0, len(cases))
+	for _, c := range cases {
+		eNames = append(eNames, c.EName)
+	}
+	g.recording.Answers.node(node).Case = strings.Join(eNames, ",")
+
+	return g.saveRecording()
+}
+
+// recordConfirm records the answer of the yes/no question about the subject.
+func (g *Gojen) recordConfirm(q Question, subject string, ok bool) error {
+	g.recording.Answers.Confirm[string(q)+":"+subject] = ok
+
+	return g.saveRecording()
+}
+
+// saveRecording writes the recording into the build directory.
+func (g *Gojen) saveRecording() error {
+	dir := filepath.Join(g.cfg.storePath, g.buildID)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	b, err := yaml.Marshal(g.recording)
+	if err != nil {
+		return err
+	}
+
+	return g.f.TruncWithContent(filepath.Join(dir, recordingFileName), string(b))
+}
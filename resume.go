@@ -0,0 +1,179 @@
+package gojen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// LoadStates loads the states written into the state directory of a build,
+// in the order they were built.
+func LoadStates(dir string) ([]*State, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type indexedState struct {
+		i int
+		s *State
+	}
+
+	states := []indexedState{}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".yaml" {
+			continue
+		}
+
+		prefix, _, _ := strings.Cut(e.Name(), "_")
+		i, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid state file name '%s', expected <index>_<decl>_<element>.yaml", e.Name())
+		}
+
+		path := filepath.Join(dir, e.Name())
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		s := &State{}
+		if err := yaml.Unmarshal(b, s); err != nil {
+			return nil, fmt.Errorf("error parsing state '%s': %w", path, err)
+		}
+		states = append(states, indexedState{i: i, s: s})
+	}
+
+	sort.Slice(states, func(a, b int) bool { return states[a].i < states[b].i })
+
+	res := make([]*State, 0, len(states))
+	for _, s := range states {
+		res = append(res, s.s)
+	}
+
+	return res, nil
+}
+
+// SetStates adds the states to be applied, e.g. the states loaded by
+// LoadStates from a build of another instance.
+func (g *Gojen) SetStates(states ...*State) {
+	for _, s := range states {
+		g.s.AddState(s)
+	}
+}
+
+// ApplyFrom loads the states written into the state directory of a build and
+// applies them in the order they were built. The declarations are not needed,
+// the states contain the parsed templates.
+func (g *Gojen) ApplyFrom(dir string) error {
+	states, err := LoadStates(dir)
+	if err != nil {
+		return err
+	}
+	if len(states) == 0 {
+		return fmt.Errorf("no states found in '%s'", dir)
+	}
+
+	g.SetStates(states...)
+
+	return g.Apply()
+}
+
+// Resume continues a failed build of the sequence recorded in the build. The
+// nodes which were done are restored from the state directory instead of
+// being built again, the recorded answers are used so nothing is asked twice.
+// The build continues from the node which failed, in the same build
+// directory.
+func (g *Gojen) Resume(buildID string) error {
+	rec, err := LoadRecording(g.cfg.storePath, buildID)
+	if err != nil {
+		return err
+	}
+
+	stateDir := filepath.Join(g.cfg.storePath, buildID, "state")
+	states, err := LoadStates(stateDir)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	g.buildID = buildID
+	g.localStateDir = stateDir
+	g.recording = rec
+	g.resumed = states
+	g.resumedNodes = rec.Nodes
+	g.cfg.answers = rec.Answers
+	g.UpdateArgs(rec.Args)
+
+	g.c.Infof(!g.cfg.silent, "Resuming build '%s' after %d built states\n", buildID, len(states))
+
+	return g.Build(rec.Seq)
+}
+
+// restoreNode restores the states of a node which is done by the resumed
+// build, before its args are resolved and its condition is evaluated, so
+// nothing is asked again and its cases are selected by the restored args. A
+// node without states was skipped, it is skipped again. It reports false if
+// the node is not done, e.g. it failed, then it is built again.
+func (g *Gojen) restoreNode(seq *Seq, decl *D, declElem *T, node string, i *int) (Args, bool, error) {
+	if !slices.Contains(g.resumedNodes, node) {
+		return nil, false, nil
+	}
+
+	var (
+		storeArgs, _ = g.s.GetArgs()
+		args         = NewArgs(storeArgs, decl.Args, declElem.Args, seq.TempArgs)
+		restored     = 0
+	)
+	for ; *i < len(g.resumed) && g.resumed[*i].Node == node; *i++ {
+		st := g.resumed[*i]
+		if _, err := g.restoreState(seq, decl, declElem, *i); err != nil {
+			return nil, false, err
+		}
+		if restored == 0 {
+			args.Merge(st.Args)
+			if seq.LoopArg != "" {
+				delete(args, seq.LoopAs)
+			}
+		}
+		g.s.UpdateArgs(st.ForwardedArgs)
+		restored++
+	}
+
+	if restored == 0 {
+		g.c.Infof(!g.cfg.silent, "Skipped state: %s.%s\n", decl.Name, declElem.Name)
+	}
+
+	// the cases are selected as recorded, even if the select arg is changed.
+	if selected, ok := g.cfg.answers.Case(node, seq.DName, seq.EName); ok && seq.SelectArg != "" {
+		args[seq.SelectArg] = selected
+	}
+
+	return args, true, nil
+}
+
+// restoreState adds the resumed state of the index instead of building it.
+// It reports false if there is no resumed state of the index.
+func (g *Gojen) restoreState(seq *Seq, decl *D, declElem *T, i int) (bool, error) {
+	if i >= len(g.resumed) {
+		return false, nil
+	}
+
+	st := g.resumed[i]
+	if st.DName != decl.Name || st.EName != declElem.Name {
+		return false, fmt.Errorf("build '%s' can not be resumed: state %d is '%s.%s', but the sequence builds '%s.%s'", g.buildID, i, st.DName, st.EName, decl.Name, declElem.Name)
+	}
+
+	st.seq = seq
+	st.d = decl
+	st.e = declElem
+	g.s.AddState(st)
+	g.c.Infof(!g.cfg.silent, "Restored state: %s.%s\n", decl.Name, declElem.Name)
+
+	return true, nil
+}
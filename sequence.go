@@ -25,7 +25,15 @@ type Seq struct {
 	IsCase      bool                     `yaml:"is_case,omitempty"`      // is case.
 	Next        *Seq                     `yaml:"next,omitempty"`
 	Cases       SeqCases                 `yaml:"cases,omitempty"`
-	tempArgs    Args                     `yaml:"tempArgs,omitempty"`
+	DefaultCase string                   `yaml:"default_case,omitempty"` // case selected if no case is given.
+	MultiSelect bool                     `yaml:"multi_select,omitempty"` // allow selecting several cases.
+	SelectArg   string                   `yaml:"select_arg,omitempty"`   // arg whose value selects the cases.
+	TempArgs    Args                     `yaml:"temp_args,omitempty"`    // args of this node only.
+	LoopArg     string                   `yaml:"loop_arg,omitempty"`     // list arg which the node is built for each item of.
+	LoopAs      string                   `yaml:"loop_as,omitempty"`      // temp arg which each item is bound to.
+	Cond        string                   `yaml:"if,omitempty"`           // condition, the node is skipped if it is false.
+	ID          string                   `yaml:"id,omitempty"`           // id of the node in a recording.
+	Ref         string                   `yaml:"ref,omitempty"`          // id of the shared node which is written before in a recording.
 }
 
 // SeqCases is a map of cases.
@@ -103,7 +111,88 @@ func (s *Seq) AppendWiths(chainArgs []Args, dname string, moreENames ...string)
 
 func (s *Seq) AppendWith(args Args, dname string, moreENames ...string) *Seq {
 	s = s.Append(dname, moreENames...)
-	s.tempArgs = args
+	s.TempArgs = args
+	return s
+}
+
+// AppendLoop appends the elements like Append. Each element is built once per
+// item of the list arg listArg, the item is bound to the temp arg itemArg.
+func (s *Seq) AppendLoop(listArg, itemArg, dName string, moreENames ...string) *Seq {
+	if len(moreENames) == 0 {
+		panic("no element name provided")
+	}
+	cur := s
+	for _, eName := range moreENames {
+		cur = cur.append(dName, eName).Loop(listArg, itemArg)
+	}
+	return cur
+}
+
+// Loop builds the node once per item of the list arg listArg, the item is
+// bound to the temp arg itemArg.
+func (s *Seq) Loop(listArg, itemArg string) *Seq {
+	s.LoopArg = listArg
+	s.LoopAs = itemArg
+	return s
+}
+
+// Include appends a copy of the sequence of sub, so a sequence can be
+// reused in several sequences. The args override the temp args of every node
+// of the copy. It returns the copy of sub.
+func (s *Seq) Include(sub *Seq, args Args) *Seq {
+	c := sub.copyWith(args)
+	first := c.root
+	first.link(s.root, s.cfg)
+	for _, l := range s.AllLast() {
+		l.Next = first
+	}
+	return c
+}
+
+// copyWith copies the whole sequence of s and merges the args into the temp
+// args of every node. It returns the copy of s.
+func (s *Seq) copyWith(args Args) *Seq {
+	copies := map[*Seq]*Seq{}
+
+	var cp func(n *Seq) *Seq
+	cp = func(n *Seq) *Seq {
+		if n == nil {
+			return nil
+		}
+		// the nodes appended after a multi-select are shared by all cases.
+		if c, ok := copies[n]; ok {
+			return c
+		}
+
+		c := *n
+		copies[n] = &c
+		c.ForwardArgs = util.MapExisting[string]{}
+		for k := range n.ForwardArgs {
+			c.ForwardArgs.Add(k)
+		}
+		if len(args) > 0 {
+			c.TempArgs = NewArgs(n.TempArgs, args)
+		}
+		c.Next = cp(n.Next)
+		c.Cases = make(SeqCases, len(n.Cases))
+		for k, v := range n.Cases {
+			c.Cases[k] = cp(v)
+		}
+		return &c
+	}
+
+	root := cp(s.root)
+	root.link(root, s.cfg)
+
+	return copies[s]
+}
+
+// If sets the condition of the node. The condition is a template expression
+// evaluated against the args of the node, e.g. '.WithView' or
+// 'eq .Method "get"'. The node is skipped if it is false, the sequence
+// continues with the next node or the cases.
+func (s *Seq) If(cond string) *Seq {
+	s.Cond = cond
 	return s
 }
 
@@ -146,6 +235,28 @@ func (s *Seq) Select(dName string, eNames []string, handler func(ss SeqSwitcher)
 	return s
 }
 
+// SelectMulti is like Select, but several cases can be selected and each of
+// them continues into its own chain.
+func (s *Seq) SelectMulti(dName string, eNames []string, handler func(ss SeqSwitcher)) *Seq {
+	s.MultiSelect = true
+	return s.Select(dName, eNames, handler)
+}
+
+// SelectBy is like Select, but the case is selected by the value of the arg.
+// The value is the element name of the case, or a list of element names if
+// the node allows multi-select. The case is asked only if the arg is absent.
+func (s *Seq) SelectBy(argName, dName string, eNames []string, handler func(ss SeqSwitcher)) *Seq {
+	s.SelectArg = argName
+	return s.Select(dName, eNames, handler)
+}
+
+// Default sets the case which is selected if no case is given. Several cases
+// of a multi-select are separated by comma.
+func (s *Seq) Default(eName string) *Seq {
+	s.DefaultCase = eName
+	return s
+}
+
 func (s *Seq) When(eName string, handler util.PRFunc[*Seq, *Seq]) SeqSwitcher {
 	if c, exists := s.Cases[eName]; exists {
 		return handler(c)
@@ -153,6 +264,184 @@ func (s *Seq) When(eName string, handler util.PRFunc[*Seq, *Seq]) SeqSwitcher {
 	return s
 }
 
+// Root returns the root of the sequence.
+func (s *Seq) Root() *Seq {
+	return s.root
+}
+
+// link sets the root and config of all nodes of a sequence which is decoded
+// from a file.
+func (s *Seq) link(root *Seq, cfg *SeqConfig) {
+	s.root = root
+	s.cfg = cfg
+	if s.ForwardArgs == nil {
+		s.ForwardArgs = util.MapExisting[string]{}
+	}
+	if s.Cases == nil {
+		s.Cases = SeqCases{}
+	}
+
+	if s.Next != nil {
+		s.Next.link(root, cfg)
+	}
+	for _, c := range s.Cases {
+		c.link(root, cfg)
+	}
+}
+
+// nodeIDs returns the ids of all nodes of the sequence, numbered in depth
+// first order from the root with the cases sorted by name. The ids only depend
+// on the shape of the sequence, so they are the same in every build of it.
+func (s *Seq) nodeIDs() map[*Seq]string {
+	var (
+		ids   = map[*Seq]string{}
+		visit func(n *Seq)
+	)
+
+	visit = func(n *Seq) {
+		if _, ok := ids[n]; ok {
+			return
+		}
+		ids[n] = fmt.Sprintf("n%d", len(ids))
+
+		if len(n.Cases) == 0 {
+			if n.Next != nil {
+				visit(n.Next)
+			}
+			return
+		}
+		util.LoopStrMap(n.Cases, func(_ string, c *Seq) {
+			visit(c)
+		})
+	}
+	visit(s.root)
+
+	return ids
+}
+
+// sharedNodes returns the nodes which are reached from several cases of the
+// node, e.g. the nodes appended after a multi-select.
+func (s *Seq) sharedNodes() map[*Seq]bool {
+	var (
+		reached = map[*Seq]int{}
+		visit   func(n *Seq, seen map[*Seq]bool)
+	)
+
+	visit = func(n *Seq, seen map[*Seq]bool) {
+		if n == nil || seen[n] {
+			return
+		}
+		seen[n] = true
+		reached[n]++
+
+		visit(n.Next, seen)
+		for _, c := range n.Cases {
+			visit(c, seen)
+		}
+	}
+	for _, c := range s.Cases {
+		visit(c, map[*Seq]bool{})
+	}
+
+	shared := map[*Seq]bool{}
+	for n, count := range reached {
+		if count > 1 {
+			shared[n] = true
+		}
+	}
+
+	return shared
+}
+
+// recorded returns a copy of the whole sequence to be written in a recording.
+// Each node has its id. The nodes appended after a multi-select are shared by
+// all cases, they are written once and the others only refer to their ids.
+func (s *Seq) recorded() *Seq {
+	var (
+		ids     = s.nodeIDs()
+		written = map[*Seq]bool{}
+		cp      func(n *Seq) *Seq
+	)
+
+	cp = func(n *Seq) *Seq {
+		if n == nil {
+			return nil
+		}
+		if written[n] {
+			return &Seq{DName: n.DName, EName: n.EName, Ref: ids[n]}
+		}
+		written[n] = true
+
+		c := *n
+		c.ID = ids[n]
+		c.Next = cp(n.Next)
+		c.Cases = make(SeqCases, len(n.Cases))
+		util.LoopStrMap(n.Cases, func(k string, v *Seq) {
+			c.Cases[k] = cp(v)
+		})
+		return &c
+	}
+
+	return cp(s.root)
+}
+
+// unref replaces the nodes of a recorded sequence which refer to the id of a
+// shared node by the node, so the node is shared by all cases again.
+func (s *Seq) unref() error {
+	var (
+		nodes   = map[string]*Seq{}
+		collect func(n *Seq)
+		resolve func(n *Seq) (*Seq, error)
+		done    = map[*Seq]bool{}
+	)
+
+	collect = func(n *Seq) {
+		if n == nil {
+			return
+		}
+		if n.ID != "" && n.Ref == "" {
+			nodes[n.ID] = n
+		}
+		collect(n.Next)
+		for _, c := range n.Cases {
+			collect(c)
+		}
+	}
+	collect(s)
+
+	resolve = func(n *Seq) (*Seq, error) {
+		if n == nil {
+			return nil, nil
+		}
+		if n.Ref != "" {
+			shared, ok := nodes[n.Ref]
+			if !ok {
+				return nil, fmt.Errorf("node '%s.%s' refers to unknown node '%s'", n.DName, n.EName, n.Ref)
+			}
+			n = shared
+		}
+		if done[n] {
+			return n, nil
+		}
+		done[n] = true
+
+		next, err := resolve(n.Next)
+		if err != nil {
+			return nil, err
+		}
+		n.Next = next
+		for k, c := range n.Cases {
+			if n.Cases[k], err = resolve(c); err != nil {
+				return nil, err
+			}
+		}
+		return n, nil
+	}
+	_, err := resolve(s)
+
+	return err
+}
+
 func (s *Seq) String() string {
 	return strings.Join(s.root.string(""), "\n")
 }
@@ -163,6 +452,12 @@ func (s *Seq) string(indent string) []string {
 
 	travel = func(n *Seq, indent string) {
 		myPath := fmt.Sprintf("%s -> %s.%s", indent, n.DName, n.EName)
+		if n.LoopArg != "" {
+			myPath += fmt.Sprintf(" (for %s in %s)", n.LoopAs, n.LoopArg)
+		}
+		if n.Cond != "" {
+			myPath += fmt.Sprintf(" (if %s)", n.Cond)
+		}
 		if len(n.Cases) == 0 {
 			if n.Next == nil {
 				res = append(res, myPath)
@@ -175,7 +470,13 @@ func (s *Seq) string(indent string) []string {
 			travel(n.Next, myPath)
 			return
 		}
-		myPath += " -> (select cases)"
+		if n.SelectArg != "" {
+			myPath += fmt.Sprintf(" -> (select cases by %s)", n.SelectArg)
+		} else if n.MultiSelect {
+			myPath += " -> (select multiple cases)"
+		} else {
+			myPath += " -> (select cases)"
+		}
 		res = append(res, myPath)
 		branchIndent := util.MkSpace(len(myPath))
 
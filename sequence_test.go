@@ -2,9 +2,13 @@ package gojen_test
 
 import (
 	"fmt"
+	"path/filepath"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/cirius-go/gojen"
+	"github.com/cirius-go/gojen/util/testlib"
 )
 
 func TestSequence(t *testing.T) {
@@ -37,3 +41,156 @@ func TestSequence(t *testing.T) {
 	//                                                                                                                          4) -> api.post
 	//                                                                                                                          5) -> api.put
 }
+
+func TestLoadSequence(t *testing.T) {
+	dirPath := testlib.CreateDir(t, "decls")
+	testlib.NewFileWithContent(t, filepath.Join(dirPath, "service.seq.yaml"), `
+name: service
+description: Service recipe
+steps:
+  - d_name: service
+    e_names: [init]
+    cases:
+      crud:
+        - d_name: dto
+          e_names: [init, crud]
+        - d_name: dto
+          e_names: [method]
+          loop: Methods
+          loop_as: Method
+      singleMethod:
+        - d_name: dto
+          e_names: [init, singleMethod]
+        - d_name: api
+          e_names: [handler]
+          with:
+            - Method: get
+            - Method: post
+  - d_name: api
+    e_names: [register]
+    forward_args: [Domain]
+`)
+
+	g := gojen.NewWithConfig(gojen.C().SetSilent(true).SetStorePath(t.TempDir()))
+	assert.Nil(t, g.LoadDecls(dirPath))
+
+	s, err := g.Seq("service")
+	assert.Nil(t, err)
+
+	expected := gojen.
+		NewSeq("service", "init").
+		Select("service", []string{"crud", "singleMethod"}, func(ss gojen.SeqSwitcher) {
+			ss.When("crud", func(c *gojen.Seq) *gojen.Seq {
+				return c.
+					Append("dto", "init", "crud").
+					AppendLoop("Methods", "Method", "dto", "method")
+			})
+
+			ss.When("singleMethod", func(c *gojen.Seq) *gojen.Seq {
+				return c.
+					Append("dto", "init", "singleMethod").
+					AppendWiths([]gojen.Args{{"Method": "get"}, {"Method": "post"}}, "api", "handler")
+			})
+		}).
+		Append("api", "register").
+		Forward("Domain")
+
+	assert.Equal(t, expected.String(), s.String())
+	assert.Equal(t, []string{"Domain"}, s.ForwardArgs.Keys())
+	assert.Contains(t, s.String(), "dto.method (for Method in Methods)")
+
+	_, err = g.Seq("unknown")
+	assert.NotNil(t, err)
+}
+
+func TestIncludeSequence(t *testing.T) {
+	dirPath := testlib.CreateDir(t, "decls")
+	testlib.NewFileWithContent(t, filepath.Join(dirPath, "svc.seq.yaml"), `
+name: svc
+steps:
+  - d_name: svc
+    e_names: [initIntfFile, createIntf, initSvcFile]
+`)
+	testlib.NewFileWithContent(t, filepath.Join(dirPath, "crud.seq.yaml"), `
+name: crud
+steps:
+  - d_name: model
+    e_names: [init]
+  - include: svc
+    with:
+      - Domain: customer
+  - d_name: api
+    e_names: [init]
+`)
+	testlib.NewFileWithContent(t, filepath.Join(dirPath, "loop.seq.yaml"), `
+name: loop
+steps:
+  - include: loop
+`)
+
+	g := gojen.NewWithConfig(gojen.C().SetSilent(true).SetStorePath(t.TempDir()))
+	assert.Nil(t, g.LoadDecls(dirPath))
+
+	s, err := g.Seq("crud")
+	assert.Nil(t, err)
+
+	svc := gojen.NewSeq("svc", "initIntfFile", "createIntf", "initSvcFile")
+	expected := gojen.
+		NewSeq("model", "init").
+		Include(svc, gojen.Args{"Domain": "customer"}).
+		Append("api", "init")
+
+	assert.Equal(t, expected.String(), s.String())
+
+	// the included nodes are copies with the overridden args.
+	n := s.Root().Next
+	assert.Equal(t, "initIntfFile", n.EName)
+	assert.Equal(t, gojen.Args{"Domain": "customer"}, n.TempArgs)
+	assert.Equal(t, s.Root(), n.Root())
+	assert.Nil(t, svc.Root().TempArgs)
+
+	_, err = g.Seq("loop")
+	assert.ErrorContains(t, err, "sequence 'loop' includes itself: loop -> loop")
+}
+
+func TestSequenceGraph(t *testing.T) {
+	s := gojen.
+		NewSeq("svc", "init").
+		Forward("Domain").
+		SelectBy("Kind", "svc", []string{"crud", "readonly"}, func(ss gojen.SeqSwitcher) {
+			ss.When("crud", func(c *gojen.Seq) *gojen.Seq {
+				return c.AppendWith(gojen.Args{"Method": "create"}, "svc", "handler")
+			})
+		}).
+		Default("readonly").
+		Append("api", "register").
+		If(".Register")
+
+	assert.Equal(t, `flowchart TD
+  n0{"svc.init<br/>select by Kind"}
+  n1["svc.crud"]
+  n2["svc.handler"]
+  n3["api.register<br/>if .Register"]
+  n4["svc.readonly"]
+  n0 -->|"Kind=crud<br/>forward: Domain"| n1
+  n1 -->|"with: Method=create"| n2
+  n2 --> n3
+  n0 -->|"Kind=readonly (default)<br/>forward: Domain"| n4
+  n4 --> n3
+`, s.Mermaid())
+
+	assert.Equal(t, `digraph seq {
+  node [shape=box];
+  n0 [label="svc.init\nselect by Kind", shape=diamond];
+  n1 [label="svc.crud"];
+  n2 [label="svc.handler"];
+  n3 [label="api.register\nif .Register"];
+  n4 [label="svc.readonly"];
+  n0 -> n1 [label="Kind=crud\nforward: Domain"];
+  n1 -> n2 [label="with: Method=create"];
+  n2 -> n3;
+  n0 -> n4 [label="Kind=readonly (default)\nforward: Domain"];
+  n4 -> n3;
+}
+`, s.DOT())
+}
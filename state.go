@@ -9,6 +9,7 @@ type (
 		d   *D   `yaml:"-"`
 		e   *T   `yaml:"-"`
 
+		Node          string             `yaml:"node,omitempty"` // id of the node of the sequence which built the state.
 		Strategy      Strategy           `yaml:"strategy"`
 		DName         string             `yaml:"d_name"`
 		EName         string             `yaml:"e_name"`
@@ -21,6 +22,7 @@ type (
 		ParsedPath    string             `yaml:"parsed_path"`
 		ParsedTmpl    string             `yaml:"parsed_tmpl"`
 		Output        map[string]*Output `yaml:"output"`
+		MissingArgs   []string           `yaml:"missing_args,omitempty"` // args referenced but not provided, in MissingKeyWarn mode.
 	}
 )
 
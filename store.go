@@ -3,6 +3,9 @@ package gojen
 import (
 	"encoding/json"
 	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"gopkg.in/yaml.v2"
 
@@ -23,12 +26,17 @@ type (
 	store struct {
 		cfg         *StoreConfig
 		decls       map[string]*D
+		seqs        map[string]*S
 		args        Args
 		builtStates []*State
 
-		fm FileManager
-		c  ConsoleManager
+		fm      FileManager
+		c       ConsoleManager
+		confirm confirmFunc
 	}
+
+	// confirmFunc asks a yes/no question about the subject.
+	confirmFunc func(q Question, subject string, msg string, args ...any) (bool, error)
 )
 
 // NewArgs returns a new Args.
@@ -96,10 +104,14 @@ func NewStoreWithConfig(cfg *StoreConfig, c ConsoleManager, fm FileManager) *sto
 	return &store{
 		cfg:         cfg,
 		decls:       make(map[string]*D),
+		seqs:        make(map[string]*S),
 		args:        make(Args),
 		builtStates: []*State{},
 		fm:          fm,
 		c:           c,
+		confirm: func(_ Question, _ string, msg string, args ...any) (bool, error) {
+			return c.PerformYesNo(msg, args...), nil
+		},
 	}
 }
 
@@ -111,8 +123,33 @@ func NewStore(c ConsoleManager, fm FileManager) *store {
 }
 
 // LoadDir walks through the directory and loads the template definitions.
+// The elements declared by the front matter of template files are grouped by
+// their declaration, they are added to the declaration of the same name in
+// the directory, or to a new declaration.
 func (s *store) LoadDir(dir string) error {
-	return s.fm.WalkDir(dir, true, func(e *filemanager.FileInfo) error {
+	var (
+		decls    = []*D{}
+		elements = map[string][]*T{}
+		names    = []string{}
+	)
+
+	err := s.fm.WalkDir(dir, true, func(e *filemanager.FileInfo) error {
+		if isFrontMatterFile(e.Name) {
+			fm, err := parseFrontMatter(e.File)
+			if err != nil {
+				return fmt.Errorf("error loading template '%s': %w", e.Path, err)
+			}
+
+			dName := util.IfValue(filepath.Base(dir), fm.Decl)
+			if _, ok := elements[dName]; !ok {
+				names = append(names, dName)
+			}
+			fm.T.source = e.Path
+			elements[dName] = append(elements[dName], &fm.T)
+
+			return nil
+		}
+
 		var fileDecoder FileDecoder
 
 		switch e.Ext {
@@ -124,21 +161,81 @@ func (s *store) LoadDir(dir string) error {
 			return nil
 		}
 
+		if isSeqFile(e.Name) {
+			sd := &S{}
+			if err := fileDecoder.Decode(&sd); err != nil {
+				return err
+			}
+
+			if err := sd.Validate(); err != nil {
+				return fmt.Errorf("error validating sequence '%s': %w", e.Path, err)
+			}
+
+			ok, err := s.setSeq(sd)
+			if err != nil {
+				return err
+			}
+			if ok {
+				s.c.Infof(s.cfg.silent, "Loaded sequence definition from: '%s'\n", e.Path)
+			}
+
+			return nil
+		}
+
 		d := &D{}
 		if err := fileDecoder.Decode(&d); err != nil {
 			return err
 		}
+		d.source = e.Path
+		decls = append(decls, d)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, dName := range names {
+		i := slices.IndexFunc(decls, func(d *D) bool { return d.Name == dName })
+		if i < 0 {
+			decls = append(decls, &D{Name: dName, source: dir})
+			i = len(decls) - 1
+		}
+
+		d := decls[i]
+		for _, e := range elements[dName] {
+			if d.GetElements(e.Name) != nil {
+				return fmt.Errorf("error loading template '%s': element '%s' is already declared in '%s'", e.source, e.Name, d.source)
+			}
+			d.Templates = append(d.Templates, e)
+		}
+	}
+
+	for _, d := range decls {
+		if err := d.LoadTemplateFiles(dir); err != nil {
+			return fmt.Errorf("error loading template '%s': %w", d.source, err)
+		}
 
 		if err := d.Validate(); err != nil {
-			return fmt.Errorf("error validating template '%s': %w", e.Path, err)
+			return fmt.Errorf("error validating template '%s': %w", d.source, err)
 		}
 
-		if s.SetDecl(d) {
-			s.c.Infof(s.cfg.silent, "Loaded template definition from: '%s'\n", e.Path)
+		ok, err := s.setDecl(d)
+		if err != nil {
+			return err
+		}
+		if ok {
+			s.c.Infof(s.cfg.silent, "Loaded template definition from: '%s'\n", d.source)
 		}
+	}
 
-		return nil
-	})
+	return nil
+}
+
+// isSeqFile reports whether the file contains a sequence definition, which is
+// named as <name>.seq.(yaml|yml|json).
+func isSeqFile(name string) bool {
+	return strings.HasSuffix(strings.TrimSuffix(name, filepath.Ext(name)), ".seq")
 }
 
 // Get returns the template definition with the given name.
@@ -146,22 +243,83 @@ func (s *store) GetDecl(name string) *D {
 	return s.decls[name]
 }
 
+// GetDecls returns all template definitions sorted by name.
+func (s *store) GetDecls() []*D {
+	res := make([]*D, 0, len(s.decls))
+	util.LoopStrMap(s.decls, func(_ string, d *D) {
+		res = append(res, d)
+	})
+
+	return res
+}
+
 // SetDecl stores the template definition in the map.
 func (s *store) SetDecl(d *D) bool {
+	ok, err := s.setDecl(d)
+	if err != nil {
+		s.c.Dangerf(true, "%s\n", err)
+	}
+
+	return ok
+}
+
+func (s *store) setDecl(d *D) (bool, error) {
 	if d.Name == "" {
 		s.c.Warnf(s.cfg.silent, "Template name is required. Skipping...\n")
-		return false
+		return false, nil
 	}
 
 	if _, ok := s.decls[d.Name]; ok {
-		r := s.c.PerformYesNo("Declaration with name '%s' already exists. Do you want to override it?\n", d.Name)
-		if !r {
-			return false
+		r, err := s.confirm(QuestionOverrideDecl, d.Name, "Declaration with name '%s' already exists. Do you want to override it?\n", d.Name)
+		if err != nil || !r {
+			return false, err
 		}
 	}
 
 	s.decls[d.Name] = d
-	return true
+	return true, nil
+}
+
+// GetSeq returns the sequence definition with the given name.
+func (s *store) GetSeq(name string) *S {
+	return s.seqs[name]
+}
+
+// GetSeqs returns all sequence definitions sorted by name.
+func (s *store) GetSeqs() []*S {
+	res := make([]*S, 0, len(s.seqs))
+	util.LoopStrMap(s.seqs, func(_ string, sd *S) {
+		res = append(res, sd)
+	})
+
+	return res
+}
+
+// SetSeq stores the sequence definition in the map.
+func (s *store) SetSeq(sd *S) bool {
+	ok, err := s.setSeq(sd)
+	if err != nil {
+		s.c.Dangerf(true, "%s\n", err)
+	}
+
+	return ok
+}
+
+func (s *store) setSeq(sd *S) (bool, error) {
+	if sd.Name == "" {
+		s.c.Warnf(s.cfg.silent, "Sequence name is required. Skipping...\n")
+		return false, nil
+	}
+
+	if _, ok := s.seqs[sd.Name]; ok {
+		r, err := s.confirm(QuestionOverrideSeq, sd.Name, "Sequence with name '%s' already exists. Do you want to override it?\n", sd.Name)
+		if err != nil || !r {
+			return false, err
+		}
+	}
+
+	s.seqs[sd.Name] = sd
+	return true, nil
 }
 
 // GetArgs returns the args.
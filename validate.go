@@ -0,0 +1,148 @@
+package gojen
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/cirius-go/gojen/util"
+)
+
+// Validate walks every branch of the sequence without building it, and
+// reports all unknown declarations, elements, strategies and cases, and the
+// required args which are not given by the store, the answers, the
+// declaration, the temp args, the param defaults, a loop or a forward of a
+// previous node.
+func (g *Gojen) Validate(seq *Seq) error {
+	var (
+		errs   = []error{}
+		seen   = map[string]bool{}
+		report = func(err error) {
+			if !seen[err.Error()] {
+				seen[err.Error()] = true
+				errs = append(errs, err)
+			}
+		}
+		storeArgs, _ = g.s.GetArgs()
+		nodeIDs      = seq.nodeIDs()
+		travel       func(n *Seq, forwarded util.MapExisting[string], path map[*Seq]bool)
+	)
+
+	travel = func(n *Seq, forwarded util.MapExisting[string], path map[*Seq]bool) {
+		// the nodes appended after a multi-select are reached by every case.
+		if path[n] {
+			return
+		}
+		path[n] = true
+		defer delete(path, n)
+
+		forwarded = g.validateNode(n, nodeIDs[n], storeArgs, forwarded, report)
+
+		if len(n.Cases) == 0 {
+			if n.Next != nil {
+				travel(n.Next, forwarded, path)
+			}
+			return
+		}
+
+		if n.DefaultCase != "" {
+			if _, err := n.selectCases(n.DefaultCase, nil); err != nil {
+				report(fmt.Errorf("invalid default case: %w", err))
+			}
+		}
+		util.LoopStrMap(n.Cases, func(_ string, c *Seq) {
+			travel(c, forwarded, path)
+		})
+	}
+	travel(seq.root, util.MapExisting[string]{}, map[*Seq]bool{})
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return &ErrInvalidSeq{DName: seq.root.DName, EName: seq.root.EName, Errs: errs}
+}
+
+// validateNode reports the problems of the node with the given id and returns
+// the forwarded args after the node is built. A skipped node forwards nothing,
+// but a node is not skipped if its condition reads an arg which is only known
+// during the build, e.g. a forwarded arg.
+func (g *Gojen) validateNode(n *Seq, node string, storeArgs Args, forwarded util.MapExisting[string], report func(err error)) util.MapExisting[string] {
+	next := util.MapExisting[string]{}
+	for k := range forwarded {
+		next.Add(k)
+	}
+	forward := func() util.MapExisting[string] {
+		for k := range n.ForwardArgs {
+			next.Add(k)
+		}
+		return next
+	}
+
+	decl := g.s.GetDecl(n.DName)
+	if decl == nil {
+		report(&ErrDeclNotFound{DName: n.DName})
+		return forward()
+	}
+	declElem := decl.GetElements(n.EName)
+	if declElem == nil {
+		report(&ErrElementNotFound{DName: n.DName, EName: n.EName})
+		return forward()
+	}
+
+	if !declElem.Strategy.IsValid() {
+		report(&ErrUnknownStrategy{DName: decl.Name, EName: declElem.Name, Strategy: declElem.Strategy})
+	}
+
+	var (
+		args     = NewArgs(storeArgs, decl.Args, declElem.Args, n.TempArgs)
+		params   = decl.Params.Merge(declElem.Params)
+		required = util.NewSlice(decl.Require, declElem.Require, n.ForwardArgs.Keys())
+		missing  = []string{}
+	)
+	args.Merge(params.Defaults(args))
+	// the conditions are evaluated as in the build, where the missing args
+	// are answered before. The forwarded args are only known during the build.
+	condArgs := NewArgs(g.cfg.answers.args(node), args)
+	for k := range forwarded {
+		delete(condArgs, k)
+	}
+	if n.LoopArg != "" {
+		args[n.LoopAs] = nil
+		required = append(required, n.LoopArg)
+	} else if skipped, err := g.skipped(n, decl, declElem, condArgs); err != nil {
+		report(err)
+		return forward()
+	} else if skipped && !g.readsUnknownArgs(n, decl, declElem, condArgs) {
+		// the args of a skipped node are not asked.
+		return next
+	}
+
+	for _, k := range required {
+		if _, ok := args[k]; ok || forwarded.Contains(k) {
+			continue
+		}
+		if _, ok := g.cfg.answers.Arg(node, k); ok {
+			continue
+		}
+		missing = append(missing, k)
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		report(&ErrMissingArg{DName: decl.Name, EName: declElem.Name, Args: slices.Compact(missing)})
+	}
+
+	return forward()
+}
+
+// readsUnknownArgs reports whether a condition of the node or of its element
+// reads an arg which is not in args.
+func (g *Gojen) readsUnknownArgs(n *Seq, decl *D, declElem *T, args Args) bool {
+	for _, c := range conditions(n, decl, declElem) {
+		if len(g.missingArgs(args, condTemplate(c.cond, c.dl), c.dl)) > 0 {
+			return true
+		}
+	}
+
+	return false
+}